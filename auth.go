@@ -0,0 +1,174 @@
+package httpc
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// AuthProvider applies authentication to outgoing request
+type AuthProvider interface {
+	// Authenticate set authentication to request. body is the composed request body
+	Authenticate(ctx context.Context, req *http.Request, body []byte) error
+}
+
+// AuthRefresher is implemented by AuthProvider that is able to refresh credentials when server responds with
+// 401 Unauthorized status. If Refresh returns true, request will be replayed once
+type AuthRefresher interface {
+	Refresh(ctx context.Context, resp *http.Response) (bool, error)
+}
+
+// NewBasicAuth creates AuthProvider that set HTTP Basic authentication header
+func NewBasicAuth(username string, password string) AuthProvider {
+	cred := base64.StdEncoding.EncodeToString([]byte(username + ":" + password))
+	return &headerAuth{value: "Basic " + cred}
+}
+
+// NewBearerAuth creates AuthProvider that set static Bearer token in authentication header
+func NewBearerAuth(token string) AuthProvider {
+	return &headerAuth{value: TokenTypeBearer + " " + token}
+}
+
+type headerAuth struct {
+	value string
+}
+
+func (a *headerAuth) Authenticate(_ context.Context, req *http.Request, _ []byte) error {
+	req.Header.Set(HeaderAuthorization, a.value)
+	return nil
+}
+
+// Token is an access token retrieved from TokenSource
+type Token struct {
+	AccessToken string
+	// TokenType is the authorization scheme of token. If empty, Bearer will be used
+	TokenType string
+	// Expiry is the time when token expires. Zero value means token never expires
+	Expiry time.Time
+}
+
+// Valid returns true if token is set and not expired
+func (t *Token) Valid() bool {
+	return t != nil && t.AccessToken != "" && !t.expired(0)
+}
+
+// HeaderValue returns value of Authorization header for the token
+func (t *Token) HeaderValue() string {
+	tt := t.TokenType
+	if tt == "" {
+		tt = TokenTypeBearer
+	}
+	return tt + " " + t.AccessToken
+}
+
+// expired returns true if token expires within delta
+func (t *Token) expired(delta time.Duration) bool {
+	if t.Expiry.IsZero() {
+		return false
+	}
+	return !time.Now().Add(delta).Before(t.Expiry)
+}
+
+// TokenSource provides token to authenticate requests
+type TokenSource interface {
+	Token(ctx context.Context) (*Token, error)
+}
+
+// TokenSourceFn is a function that implements TokenSource
+type TokenSourceFn func(ctx context.Context) (*Token, error)
+
+func (fn TokenSourceFn) Token(ctx context.Context) (*Token, error) {
+	return fn(ctx)
+}
+
+// NewCachedTokenSource creates TokenSource that reuse token retrieved from src until it expires.
+// Token is considered expired earlier by expiryDelta to avoid using a token that expires in-flight
+func NewCachedTokenSource(src TokenSource, expiryDelta time.Duration) *CachedTokenSource {
+	return &CachedTokenSource{
+		src:         src,
+		expiryDelta: expiryDelta,
+	}
+}
+
+// CachedTokenSource is a TokenSource that cache token until it expires. Safe for concurrent use
+type CachedTokenSource struct {
+	src         TokenSource
+	expiryDelta time.Duration
+	mu          sync.Mutex
+	token       *Token
+}
+
+func (s *CachedTokenSource) Token(ctx context.Context) (*Token, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.token.Valid() && !s.token.expired(s.expiryDelta) {
+		return s.token, nil
+	}
+	t, err := s.src.Token(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if t == nil || t.AccessToken == "" {
+		return nil, errors.New("httpc: TokenSource returns empty token")
+	}
+	s.token = t
+	return t, nil
+}
+
+// Invalidate discard cached token, so the next Token call will retrieve a new token from source
+func (s *CachedTokenSource) Invalidate() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.token = nil
+}
+
+// invalidateIfCurrent discard cached token only if its header value equals to v. If not equal, token has been
+// refreshed by another request
+func (s *CachedTokenSource) invalidateIfCurrent(v string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.token != nil && s.token.HeaderValue() == v {
+		s.token = nil
+	}
+}
+
+// NewTokenAuth creates AuthProvider that set token retrieved from src in authentication header.
+// Token is cached until it expires, and refreshed once when server responds with 401 Unauthorized status
+func NewTokenAuth(src TokenSource) AuthProvider {
+	cs, ok := src.(*CachedTokenSource)
+	if !ok {
+		cs = NewCachedTokenSource(src, 10*time.Second)
+	}
+	return &tokenAuth{src: cs}
+}
+
+type tokenAuth struct {
+	src *CachedTokenSource
+}
+
+func (a *tokenAuth) Authenticate(ctx context.Context, req *http.Request, _ []byte) error {
+	t, err := a.src.Token(ctx)
+	if err != nil {
+		return err
+	}
+	req.Header.Set(HeaderAuthorization, t.HeaderValue())
+	return nil
+}
+
+// Refresh discard cached token if it is the token sent on the original attempt. Authorization header is compared
+// with the original request, because it is removed from redirected request on cross-host redirect
+func (a *tokenAuth) Refresh(_ context.Context, resp *http.Response) (bool, error) {
+	a.src.invalidateIfCurrent(originalRequest(resp.Request).Header.Get(HeaderAuthorization))
+	return true, nil
+}
+
+// originalRequest returns the first request in redirect chain of req
+func originalRequest(req *http.Request) *http.Request {
+	for req.Response != nil && req.Response.Request != nil {
+		req = req.Response.Request
+	}
+	return req
+}
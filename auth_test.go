@@ -0,0 +1,148 @@
+package httpc_test
+
+import (
+	"context"
+	"fmt"
+	"github.com/nbs-go/httpc"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestBasicAuth(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		u, p, ok := r.BasicAuth()
+		if !ok || u != "user" || p != "pass" {
+			w.WriteHeader(http.StatusUnauthorized)
+		}
+	}))
+	defer srv.Close()
+	ac := httpc.MustNewClient(srv.URL, httpc.ClientBasicAuth("user", "invalid"))
+
+	// Assert client option
+	resp, _, err := ac.DoRequest(context.Background(), "GET", "/")
+	if err != nil {
+		t.Errorf("unexpected error: %s", err)
+		return
+	}
+	if resp.StatusCode != http.StatusUnauthorized {
+		t.Errorf("unexpected response status code. StatusCode = %d", resp.StatusCode)
+		return
+	}
+
+	// Assert request option override client option
+	resp, err = httpc.NewRESTRequest(ac, "GET", "/").BasicAuth("user", "pass").Do(context.Background(), nil)
+	if err != nil {
+		t.Errorf("unexpected error: %s", err)
+		return
+	}
+	if resp.StatusCode != http.StatusOK {
+		t.Errorf("unexpected response status code. StatusCode = %d", resp.StatusCode)
+	}
+}
+
+func TestTokenAuthRefresh(t *testing.T) {
+	var issued int32
+	var valid atomic.Value
+	src := httpc.TokenSourceFn(func(ctx context.Context) (*httpc.Token, error) {
+		n := atomic.AddInt32(&issued, 1)
+		return &httpc.Token{
+			AccessToken: fmt.Sprintf("token-%d", n),
+			Expiry:      time.Now().Add(time.Hour),
+		}, nil
+	})
+	var calls int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		if r.Header.Get(httpc.HeaderAuthorization) != valid.Load().(string) {
+			w.WriteHeader(http.StatusUnauthorized)
+		}
+	}))
+	defer srv.Close()
+	ac := httpc.MustNewClient(srv.URL, httpc.ClientTokenAuth(src))
+
+	// Assert token is cached
+	valid.Store("Bearer token-1")
+	for i := 0; i < 2; i++ {
+		resp, _, err := ac.DoRequest(context.Background(), "GET", "/")
+		if err != nil {
+			t.Errorf("unexpected error: %s", err)
+			return
+		}
+		if resp.StatusCode != http.StatusOK {
+			t.Errorf("unexpected response status code. StatusCode = %d", resp.StatusCode)
+			return
+		}
+	}
+	if n := atomic.LoadInt32(&issued); n != 1 {
+		t.Errorf("unexpected issued token count: %d", n)
+		return
+	}
+
+	// Assert token is refreshed and request is replayed once on 401
+	valid.Store("Bearer token-2")
+	resp, _, err := ac.DoRequest(context.Background(), "GET", "/")
+	if err != nil {
+		t.Errorf("unexpected error: %s", err)
+		return
+	}
+	if resp.StatusCode != http.StatusOK {
+		t.Errorf("unexpected response status code. StatusCode = %d", resp.StatusCode)
+		return
+	}
+	if n := atomic.LoadInt32(&calls); n != 4 {
+		t.Errorf("unexpected server call count: %d", n)
+	}
+
+	// Assert request is not replayed more than once
+	valid.Store("Bearer never")
+	resp, _, err = ac.DoRequest(context.Background(), "GET", "/")
+	if err != nil {
+		t.Errorf("unexpected error: %s", err)
+		return
+	}
+	if resp.StatusCode != http.StatusUnauthorized {
+		t.Errorf("unexpected response status code. StatusCode = %d", resp.StatusCode)
+	}
+	if n := atomic.LoadInt32(&calls); n != 6 {
+		t.Errorf("unexpected server call count: %d", n)
+	}
+}
+
+func TestTokenAuthRefreshRedirect(t *testing.T) {
+	var issued int32
+	src := httpc.TokenSourceFn(func(ctx context.Context) (*httpc.Token, error) {
+		n := atomic.AddInt32(&issued, 1)
+		return &httpc.Token{AccessToken: fmt.Sprintf("token-%d", n), Expiry: time.Now().Add(time.Hour)}, nil
+	})
+	var calls int32
+	other := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// Respond with 401 on the first call. Authorization header is not forwarded on cross-host redirect
+		if atomic.AddInt32(&calls, 1) == 1 {
+			w.WriteHeader(http.StatusUnauthorized)
+		}
+	}))
+	defer other.Close()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, "http://localhost:"+strconv.Itoa(other.Listener.Addr().(*net.TCPAddr).Port), http.StatusFound)
+	}))
+	defer srv.Close()
+	ac := httpc.MustNewClient(srv.URL, httpc.ClientTokenAuth(src))
+
+	// Assert token sent on the original attempt is invalidated
+	resp, _, err := ac.DoRequest(context.Background(), "GET", "/")
+	if err != nil {
+		t.Errorf("unexpected error: %s", err)
+		return
+	}
+	if resp.StatusCode != http.StatusOK {
+		t.Errorf("unexpected response status code. StatusCode = %d", resp.StatusCode)
+	}
+	if n := atomic.LoadInt32(&issued); n != 2 {
+		t.Errorf("unexpected issued token count: %d", n)
+	}
+}
@@ -9,10 +9,10 @@ import (
 	"fmt"
 	"github.com/google/uuid"
 	"github.com/nbs-go/nlogger/v2"
+	"github.com/nbs-go/nlogger/v2/level"
 	logOption "github.com/nbs-go/nlogger/v2/option"
 	"io"
 	"net/http"
-	"net/http/httputil"
 	"net/url"
 	"sync"
 	"time"
@@ -36,15 +36,27 @@ func SetGlobalTransporterOverrider(fn TransporterOverrider) {
 	overrideTransporter = fn
 }
 
-func NewClient(baseUrl string, args ...SetClientOptionsFn) *Client {
+// NewClient creates a Client that send requests to endpoints relative to baseUrl. It returns error if baseUrl is not
+// a valid absolute http or https url
+func NewClient(baseUrl string, args ...SetClientOptionsFn) (*Client, error) {
+	// Validate base url
+	base, err := parseBaseUrl(baseUrl)
+	if err != nil {
+		return nil, err
+	}
 	// Evaluate options
 	o := evaluateClientOptions(args)
 	// Init logger
 	cl := nlogger.Get().NewChild(logOption.WithNamespace(o.namespace))
 	// Init Client
-	c := &http.Client{}
+	c := &http.Client{
+		Jar:           o.cookieJar,
+		CheckRedirect: checkRedirect(o.redirectPolicy),
+	}
 	// Set transport
-	if o.disableHTTP2 {
+	if o.transport != nil {
+		c.Transport = o.transport
+	} else if o.disableHTTP2 {
 		c.Transport = &http.Transport{
 			TLSNextProto: map[string]func(string, *tls.Conn) http.RoundTripper{},
 		}
@@ -56,23 +68,69 @@ func NewClient(baseUrl string, args ...SetClientOptionsFn) *Client {
 	}
 	// Init client
 	return &Client{
-		baseUrl:    baseUrl,
-		httpClient: c,
-		log:        cl,
-		logDump:    o.logDump,
+		baseUrl:         baseUrl,
+		base:            base,
+		httpClient:      c,
+		log:             cl,
+		dump:            o.dump,
+		redactor:        newRedactor(o.redaction),
+		allowAbsolute:   o.allowAbsoluteUrl,
+		auth:            o.auth,
+		statusLogLevel:  o.statusLogLevel,
+		namespace:       o.namespace,
+		metrics:         o.metrics,
+		traceTimings:    o.traceTimings,
+		tracer:          o.tracer,
+		requestIdHeader: o.requestIdHeader,
+		har:             o.har,
+		logAsCurl:       o.logAsCurl,
+	}, nil
+}
+
+// MustNewClient creates a Client like NewClient, but panic if baseUrl is invalid
+func MustNewClient(baseUrl string, args ...SetClientOptionsFn) *Client {
+	c, err := NewClient(baseUrl, args...)
+	if err != nil {
+		panic(err)
 	}
+	return c
 }
 
 type Client struct {
-	baseUrl    string
-	httpClient *http.Client
-	log        nlogger.Logger
-	logDump    bool
+	baseUrl         string
+	base            *url.URL
+	httpClient      *http.Client
+	log             nlogger.Logger
+	dump            *DumpConfig
+	redactor        *redactor
+	allowAbsolute   bool
+	auth            AuthProvider
+	statusLogLevel  map[int]level.LogLevel
+	namespace       string
+	metrics         Metrics
+	traceTimings    bool
+	tracer          Tracer
+	requestIdHeader string
+	har             *HARRecorder
+	logAsCurl       bool
 }
 
 func (c *Client) DoRequest(ctx context.Context, method Method, endpointPath string, args ...SetRequestOptionFn) (*http.Response, []byte, error) {
 	o := evaluateRequestOptions(args)
-	return c.doRequest(ctx, method, endpointPath, o)
+	r, err := c.doRequest(ctx, method, endpointPath, o)
+	if err != nil {
+		return nil, nil, err
+	}
+	return r.Response, r.Body, nil
+}
+
+// Execute sends request and returns Result that contains response, body and request metadata
+func (c *Client) Execute(ctx context.Context, r *Request) (*Result, error) {
+	if r == nil {
+		return nil, errors.New("httpc: request is required")
+	}
+	o := evaluateRequestOptions(r.Options)
+	return c.doRequest(ctx, r.Method, r.EndpointPath, o)
 }
 
 func (c *Client) composeRequestBody(ctx context.Context, method Method, o *requestOptions) ([]byte, error) {
@@ -85,7 +143,7 @@ func (c *Client) composeRequestBody(ctx context.Context, method Method, o *reque
 		return body, nil
 	}
 	// Compose body by encoding-type
-	ct := o.header[HeaderContentType]
+	ct := getHeader(o.header, HeaderContentType)
 	switch ct {
 	case MimeTypeJson:
 		j, err := json.Marshal(o.body)
@@ -105,24 +163,25 @@ func (c *Client) composeRequestBody(ctx context.Context, method Method, o *reque
 	return nil, nil
 }
 
-func (c *Client) doRequest(ctx context.Context, method Method, endpointPath string, o *requestOptions) (*http.Response, []byte, error) {
+func (c *Client) doRequest(ctx context.Context, method Method, endpointPath string, o *requestOptions) (_ *Result, err error) {
 	// Validate context
 	if ctx == nil {
-		return nil, nil, errors.New("httpc: ctx is required")
+		return nil, errors.New("httpc: ctx is required")
+	}
+	// Resolve path parameters. Keep endpointPath as template for logging
+	p, err := resolvePath(endpointPath, o.pathParams)
+	if err != nil {
+		return nil, err
 	}
 	// Compose url
-	ub := bytes.NewBufferString(c.baseUrl)
-	ub.WriteString(endpointPath)
-	// If query is set, then add query params
-	if len(o.query) > 0 {
-		ub.WriteString("?")
-		ub.WriteString(o.query.Encode())
-	}
-	u := ub.String()
+	u, err := resolveUrl(c.base, p, o.query, c.allowAbsolute)
+	if err != nil {
+		return nil, err
+	}
 	// Compose request body
 	reqBody, err := c.composeRequestBody(ctx, method, o)
 	if err != nil {
-		return nil, nil, err
+		return nil, err
 	}
 	// Set timeout
 	var cancel context.CancelFunc
@@ -130,41 +189,128 @@ func (c *Client) doRequest(ctx context.Context, method Method, endpointPath stri
 	if o.timeout > 0 {
 		hCtx, cancel = context.WithTimeout(ctx, time.Duration(o.timeout)*time.Millisecond)
 	}
+	hCtx = withRedirectPolicy(hCtx, o.redirectPolicy)
 	defer func() {
 		if cancel != nil {
 			cancel()
 		}
 	}()
-	// Create request
-	req, err := http.NewRequestWithContext(hCtx, method, u, bytes.NewBuffer(reqBody))
-	if err != nil {
-		return nil, nil, err
+	// Resolve auth provider, request option takes precedence over client option
+	auth := c.auth
+	if o.auth != nil {
+		auth = o.auth
 	}
-	// Set header
-	if o.canonicalHeader {
-		for k, v := range o.header {
-			req.Header.Set(k, v)
-		}
-	} else {
-		for k, v := range o.header {
-			req.Header[k] = []string{v}
-		}
+	// Do request. Set request id in request context, so it could be retrieved from response
+	reqId := c.getRequestId(ctx)
+	hCtx = context.WithValue(hCtx, ContextRequestId, reqId)
+	if c.requestIdHeader != "" {
+		hCtx = context.WithValue(hCtx, requestIdHeaderKey{}, c.requestIdHeader)
 	}
-
-	// Call pre-request hook if set
-	if o.preRequest != nil {
-		o.preRequest(req, reqBody)
+	dc := c.resolveDumpConfig(o)
+	rl := requestLog{
+		reqId:       reqId,
+		method:      method,
+		endpoint:    endpointPath,
+		host:        u.Host,
+		startedAt:   time.Now(),
+		requestSize: len(reqBody),
 	}
-	// Do request
-	t := time.Now()
-	reqId := c.getRequestId(ctx)
-	c.logDumpRequest(ctx, req, reqId)
-	resp, err := c.httpClient.Do(req)
-	if err != nil {
-		c.log.Error("HTTP Request  (Id=%s) Failed to do request", logOption.Format(reqId), logOption.Error(err), logOption.Context(ctx))
-		return nil, nil, err
+	if c.metrics != nil {
+		ml := MetricLabels{Namespace: c.namespace, Method: method, Endpoint: endpointPath, Host: u.Host}
+		c.metrics.RequestStarted(ml)
+		defer func() {
+			ml.StatusClass = statusClass(rl.statusCode)
+			c.metrics.RequestFinished(ml, time.Since(rl.startedAt), rl.requestSize, rl.responseSize)
+		}()
+	}
+	var req *http.Request
+	var resp *http.Response
+	hCtx, rt := c.startTrace(hCtx, method, endpointPath, u, reqId)
+	defer func() {
+		rt.end(resp, &rl, err)
+	}()
+	var tt *timingsTrace
+	traceTimings := c.traceTimings || o.traceTimings
+	for attempt := 1; ; attempt++ {
+		rl.attempt = attempt
+		rCtx := rt.startAttempt(hCtx, attempt)
+		// Trace timings of each attempt. Timings that are only collected for HAR are not exposed
+		if traceTimings || c.har != nil {
+			rCtx, tt = withTimingsTrace(rCtx, traceTimings)
+		}
+		req, err = c.newRequest(rCtx, method, u, reqBody, o)
+		if err != nil {
+			return nil, err
+		}
+		rt.inject(req)
+		// Propagate request id, unless it is set explicitly in request header
+		if c.requestIdHeader != "" && req.Header.Get(c.requestIdHeader) == "" {
+			req.Header.Set(c.requestIdHeader, reqId)
+		}
+		// Call pre-request hook if set
+		if o.preRequest != nil {
+			o.preRequest(req, reqBody)
+		}
+		// Authenticate request after pre-request hook, so request signers sign the final request
+		if auth != nil {
+			err = auth.Authenticate(hCtx, req, reqBody)
+			if err != nil {
+				return nil, fmt.Errorf("httpc: Failed to authenticate request. Error = %w", err)
+			}
+		}
+		if c.logAsCurl {
+			c.logCurl(ctx, req, reqBody, reqId)
+		}
+		// Conditional dump is written after response is received, from the sent request
+		if dc != nil && !dc.conditional() {
+			c.logDumpRequest(ctx, c.dumpRequest(ctx, dc, req, reqBody, false), reqId)
+		}
+		resp, err = c.httpClient.Do(req)
+		if err != nil {
+			if dc != nil && dc.conditional() {
+				c.logDumpRequest(ctx, c.dumpRequest(ctx, dc, req, reqBody, true), reqId)
+			}
+			if traceTimings {
+				rl.timings = tt.timings()
+			}
+			c.recordHAR(req, reqBody, nil, nil, tt, err)
+			c.logFailed(ctx, &rl, err)
+			return nil, err
+		}
+		if dc != nil && dc.shouldDump(resp.StatusCode) {
+			if dc.conditional() {
+				c.logDumpRequest(ctx, c.dumpRequest(ctx, dc, req, reqBody, true), reqId)
+			}
+			respDump, dErr := c.dumpResponse(ctx, dc, resp)
+			if dErr != nil {
+				_ = resp.Body.Close()
+				rl.statusCode = resp.StatusCode
+				if tt != nil {
+					tt.finish()
+				}
+				c.recordHAR(req, reqBody, resp, nil, tt, dErr)
+				c.logFailed(ctx, &rl, dErr)
+				return nil, dErr
+			}
+			c.logDumpResponse(ctx, respDump, reqId)
+		}
+		// If unauthorized, refresh credentials and replay request once
+		replay, rErr := c.refreshAuth(hCtx, auth, resp, attempt)
+		if rErr != nil || replay {
+			c.recordDiscardedHAR(req, reqBody, resp, tt)
+		}
+		if rErr != nil {
+			c.discardResponse(ctx, resp, reqId)
+			return nil, fmt.Errorf("httpc: Failed to refresh authentication. Error = %w", rErr)
+		}
+		if !replay {
+			break
+		}
+		c.log.Debug("HTTP Request  (Id=%s) Endpoint=\"%s %s\" Replaying request after authentication refreshed",
+			logOption.Format(reqId, req.Method, endpointPath), logOption.Context(ctx))
+		rt.endAttempt(resp, nil)
+		c.discardResponse(ctx, resp, reqId)
 	}
-	c.logDumpResponse(ctx, resp, reqId)
 	// Read response body
 	defer func() {
 		wErr := resp.Body.Close()
@@ -174,51 +320,103 @@ func (c *Client) doRequest(ctx context.Context, method Method, endpointPath stri
 			)
 		}
 	}()
+	rl.statusCode = resp.StatusCode
+	rl.echoedReqId = EchoedRequestId(resp)
 	respBody, err := io.ReadAll(resp.Body)
+	if tt != nil {
+		tt.finish()
+	}
 	if err != nil {
-		return nil, nil, err
+		c.recordHAR(req, reqBody, resp, respBody, tt, err)
+		c.logFailed(ctx, &rl, err)
+		return nil, err
+	}
+	rl.responseSize = len(respBody)
+	if traceTimings {
+		rl.timings = tt.timings()
 	}
-	c.log.Debug("HTTP Request  (Id=%s) URL=\"%s %s\" ResponseStatus=\"%s\" TimeElapsed=\"%s\"",
-		logOption.Format(reqId, req.Method, req.URL.String(), resp.Status, time.Since(t)),
-		logOption.Context(ctx),
-	)
-	return resp, respBody, nil
+	c.logCompleted(ctx, &rl)
+	c.recordHAR(req, reqBody, resp, respBody, tt, nil)
+	return newResult(resp, respBody, &rl), nil
 }
 
-// getRequestId retrieve requestId value from context. If no requestId in context, then requestId wil be generated
-func (c *Client) getRequestId(ctx context.Context) string {
-	val := ctx.Value(ContextRequestId)
-	reqId, ok := val.(string)
-	if !ok {
-		return uuid.New().String()
+// newRequest creates http request with header set from request options
+func (c *Client) newRequest(ctx context.Context, method Method, u *url.URL, body []byte, o *requestOptions) (*http.Request, error) {
+	req, err := http.NewRequestWithContext(ctx, method, u.String(), bytes.NewBuffer(body))
+	if err != nil {
+		return nil, err
 	}
-	return reqId
+	// Set header
+	for k, values := range o.header {
+		if o.canonicalHeader && !o.preserveHeaderCase[k] {
+			for _, v := range values {
+				req.Header.Add(k, v)
+			}
+		} else {
+			req.Header[k] = append(req.Header[k], values...)
+		}
+	}
+	// Set cookies
+	for _, ck := range o.cookies {
+		req.AddCookie(ck)
+	}
+	return req, nil
 }
 
-func (c *Client) logDumpRequest(ctx context.Context, req *http.Request, reqId string) {
-	if !c.logDump {
-		return
+// refreshAuth refresh credentials if response status is 401 Unauthorized and returns true if request should be replayed.
+// Request is replayed at most once
+func (c *Client) refreshAuth(ctx context.Context, auth AuthProvider, resp *http.Response, attempt int) (bool, error) {
+	if resp.StatusCode != http.StatusUnauthorized || attempt > 1 {
+		return false, nil
+	}
+	r, ok := auth.(AuthRefresher)
+	if !ok {
+		return false, nil
 	}
-	dump, err := httputil.DumpRequest(req, true)
+	return r.Refresh(ctx, resp)
+}
+
+// discardResponse read remaining response body and close it, so connection can be reused
+func (c *Client) discardResponse(ctx context.Context, resp *http.Response, reqId string) {
+	_, _ = io.Copy(io.Discard, resp.Body)
+	err := resp.Body.Close()
 	if err != nil {
-		c.log.Warn("Unable to dump request. Error = %s", logOption.Format(err), logOption.Context(ctx))
-		return
+		c.log.Warn("HTTP Response (Id=%s) Failed to close Body reader. Error = %s",
+			logOption.Format(reqId, err), logOption.Context(ctx),
+		)
 	}
-	c.log.Debug("\n---------- HTTP Request Dump -----------\n(RequestId=%s)\n%s\n----------------------------------------",
-		logOption.Format(reqId, dump), logOption.Context(ctx),
-	)
 }
 
-func (c *Client) logDumpResponse(ctx context.Context, resp *http.Response, reqId string) {
-	if !c.logDump {
-		return
+type requestIdHeaderKey struct{}
+
+// RequestId returns id of request that lead to the response
+func RequestId(resp *http.Response) string {
+	if resp == nil || resp.Request == nil {
+		return ""
 	}
-	dump, err := httputil.DumpResponse(resp, true)
-	if err != nil {
-		c.log.Warn("Unable to dump response. Error = %s", logOption.Format(err), logOption.Context(ctx))
-		return
+	reqId, _ := resp.Request.Context().Value(ContextRequestId).(string)
+	return reqId
+}
+
+// EchoedRequestId returns request id that is echoed by upstream in response header. It returns empty string if
+// PropagateRequestId option is not set or upstream does not echo request id
+func EchoedRequestId(resp *http.Response) string {
+	if resp == nil || resp.Request == nil {
+		return ""
+	}
+	h, ok := resp.Request.Context().Value(requestIdHeaderKey{}).(string)
+	if !ok {
+		return ""
 	}
-	c.log.Debug("\n---------- HTTP Response Dump ----------\n(RequestId=%s)\n%s\n----------------------------------------",
-		logOption.Format(reqId, dump), logOption.Context(ctx),
-	)
+	return resp.Header.Get(h)
+}
+
+// getRequestId retrieve requestId value from context. If no requestId in context, then requestId wil be generated
+func (c *Client) getRequestId(ctx context.Context) string {
+	val := ctx.Value(ContextRequestId)
+	reqId, ok := val.(string)
+	if !ok {
+		return uuid.New().String()
+	}
+	return reqId
 }
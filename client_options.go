@@ -1,11 +1,29 @@
 package httpc
 
+import (
+	"github.com/nbs-go/nlogger/v2/level"
+	"net/http"
+)
+
 type SetClientOptionsFn func(o *clientOptions)
 
 type clientOptions struct {
-	namespace    string
-	logDump      bool
-	disableHTTP2 bool
+	namespace        string
+	dump             *DumpConfig
+	disableHTTP2     bool
+	allowAbsoluteUrl bool
+	auth             AuthProvider
+	cookieJar        http.CookieJar
+	redirectPolicy   *RedirectPolicy
+	redaction        *Redaction
+	statusLogLevel   map[int]level.LogLevel
+	metrics          Metrics
+	traceTimings     bool
+	tracer           Tracer
+	requestIdHeader  string
+	transport        http.RoundTripper
+	har              *HARRecorder
+	logAsCurl        bool
 }
 
 // Namespace override default Client namespace value
@@ -15,10 +33,85 @@ func Namespace(n string) SetClientOptionsFn {
 	}
 }
 
-// LogDump enable log HTTP request and response dump
+// LogDump enable log HTTP request and response dump, including body
 func LogDump(enable bool) SetClientOptionsFn {
 	return func(o *clientOptions) {
-		o.logDump = enable
+		if !enable {
+			o.dump = nil
+			return
+		}
+		o.dump = &DumpConfig{Level: DumpBody}
+	}
+}
+
+// LogDumpConfig set default log dump level, body limits and conditions. Could be overridden per request using Dump
+// option
+func LogDumpConfig(cfg DumpConfig) SetClientOptionsFn {
+	return func(o *clientOptions) {
+		o.dump = &cfg
+	}
+}
+
+// LogAsCurl logs every request as equivalent curl command with debug level. Log dump redaction is applied
+func LogAsCurl() SetClientOptionsFn {
+	return func(o *clientOptions) {
+		o.logAsCurl = true
+	}
+}
+
+// LogDumpRedaction set sensitive data that is masked in log dumps. By default, DefaultRedaction is applied
+func LogDumpRedaction(r Redaction) SetClientOptionsFn {
+	return func(o *clientOptions) {
+		o.redaction = &r
+	}
+}
+
+// StatusLogLevel set level of request completion log for response status class, e.g. 5 for 5xx.
+// By default, request completion is logged with debug level
+func StatusLogLevel(statusClass int, lvl level.LogLevel) SetClientOptionsFn {
+	return func(o *clientOptions) {
+		o.statusLogLevel[statusClass] = lvl
+	}
+}
+
+// CollectMetrics set Metrics to collect request count, latency, in-flight requests and body sizes.
+// Use NewPrometheusMetrics to expose metrics in Prometheus text format
+func CollectMetrics(m Metrics) SetClientOptionsFn {
+	return func(o *clientOptions) {
+		o.metrics = m
+	}
+}
+
+// Tracing set Tracer to start spans around each request and its attempts. Span context is propagated to upstream
+// in W3C traceparent header
+func Tracing(t Tracer) SetClientOptionsFn {
+	return func(o *clientOptions) {
+		o.tracer = t
+	}
+}
+
+// PropagateRequestId sends request id in header to upstream, so calls could be correlated. If headerName is empty,
+// HeaderRequestId is used. Request id that is echoed by upstream could be retrieved using EchoedRequestId
+func PropagateRequestId(headerName string) SetClientOptionsFn {
+	return func(o *clientOptions) {
+		if headerName == "" {
+			headerName = HeaderRequestId
+		}
+		o.requestIdHeader = headerName
+	}
+}
+
+// RecordHAR captures requests and responses to HARRecorder, e.g. to export traffic as .har file
+func RecordHAR(r *HARRecorder) SetClientOptionsFn {
+	return func(o *clientOptions) {
+		o.har = r
+	}
+}
+
+// ClientTraceTimings records request duration per phase for every request. See TraceTimings
+func ClientTraceTimings() SetClientOptionsFn {
+	return func(o *clientOptions) {
+		o.traceTimings = true
 	}
 }
 
@@ -29,12 +122,72 @@ func DisableHTTP2() SetClientOptionsFn {
 	}
 }
 
+// Transport set http.RoundTripper to send requests, e.g. a mock transport from httpctest package. DisableHTTP2 option
+// is ignored if transport is set. Global TransporterOverrider is still applied to the transport
+func Transport(rt http.RoundTripper) SetClientOptionsFn {
+	return func(o *clientOptions) {
+		o.transport = rt
+	}
+}
+
+// AllowAbsoluteUrl allow absolute url as endpoint path. By default, endpoint path must be relative to Client base url
+func AllowAbsoluteUrl() SetClientOptionsFn {
+	return func(o *clientOptions) {
+		o.allowAbsoluteUrl = true
+	}
+}
+
+// ClientAuth set default AuthProvider to authenticate every request. Could be overridden per request using Auth option
+func ClientAuth(p AuthProvider) SetClientOptionsFn {
+	return func(o *clientOptions) {
+		o.auth = p
+	}
+}
+
+// ClientBasicAuth set default HTTP Basic authentication for every request
+func ClientBasicAuth(username string, password string) SetClientOptionsFn {
+	return ClientAuth(NewBasicAuth(username, password))
+}
+
+// ClientBearerToken set default static Bearer token for every request
+func ClientBearerToken(token string) SetClientOptionsFn {
+	return ClientAuth(NewBearerAuth(token))
+}
+
+// ClientTokenAuth set default TokenSource to retrieve token for every request. Token is cached until it expires
+func ClientTokenAuth(src TokenSource) SetClientOptionsFn {
+	return ClientAuth(NewTokenAuth(src))
+}
+
+// DigestAuth set HTTP Digest authentication for every request. Digest challenge and nonce count are shared across
+// requests of the Client
+func DigestAuth(username string, password string) SetClientOptionsFn {
+	return ClientAuth(NewDigestAuth(username, password))
+}
+
+// CookieJar set cookie jar to store and send cookies, e.g. for session based API. Use NewCookieJar to create
+// an in-memory jar
+func CookieJar(jar http.CookieJar) SetClientOptionsFn {
+	return func(o *clientOptions) {
+		o.cookieJar = jar
+	}
+}
+
+// ClientRedirect set default redirect policy. Could be overridden per request using Redirect option
+func ClientRedirect(p RedirectPolicy) SetClientOptionsFn {
+	return func(o *clientOptions) {
+		o.redirectPolicy = &p
+	}
+}
+
 // evaluateClientOptions evaluates Client options and override default value
 func evaluateClientOptions(args []SetClientOptionsFn) *clientOptions {
+	r := DefaultRedaction()
 	o := clientOptions{
-		namespace:    "httpc",
-		logDump:      false,
-		disableHTTP2: false,
+		namespace:      "httpc",
+		disableHTTP2:   false,
+		redaction:      &r,
+		statusLogLevel: make(map[int]level.LogLevel),
 	}
 	for _, fn := range args {
 		fn(&o)
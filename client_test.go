@@ -7,6 +7,7 @@ import (
 	"encoding/json"
 	"github.com/nbs-go/httpc"
 	"net/http"
+	"net/http/httptest"
 	"net/url"
 	"strings"
 	"testing"
@@ -15,11 +16,11 @@ import (
 var c *httpc.Client
 
 func init() {
-	c = httpc.NewClient("https://httpbin.nbs.dev", httpc.Namespace("httpc_test"))
+	c = httpc.MustNewClient("https://httpbin.nbs.dev", httpc.Namespace("httpc_test"))
 }
 
 func TestTimeout(t *testing.T) {
-	ct := httpc.NewClient("https://httpbin.nbs.dev")
+	ct := httpc.MustNewClient("https://httpbin.nbs.dev")
 	_, _, err := ct.DoRequest(context.Background(), "GET", "/delay/1", httpc.Timeout(100))
 	if err.Error() != `Get "https://httpbin.nbs.dev/delay/1": context deadline exceeded` {
 		t.Errorf("unexpected error: %s", err)
@@ -205,7 +206,7 @@ func TestInvalidUrlEncodedFormBody(t *testing.T) {
 }
 
 func TestDisableHTTP2(t *testing.T) {
-	client := httpc.NewClient("https://httpbin.nbs.dev", httpc.DisableHTTP2(), httpc.LogDump(true))
+	client := httpc.MustNewClient("https://httpbin.nbs.dev", httpc.DisableHTTP2(), httpc.LogDump(true))
 	resp, _, err := client.DoRequest(context.Background(), "HEAD", "/")
 	if err != nil {
 		t.Errorf("unexpected error: %s", err)
@@ -224,9 +225,10 @@ func TestOverrideTransporter(t *testing.T) {
 			TLSNextProto: map[string]func(string, *tls.Conn) http.RoundTripper{},
 		}
 	})
+	defer httpc.SetGlobalTransporterOverrider(nil)
 
 	// Init client
-	client := httpc.NewClient("https://httpbin.nbs.dev", httpc.LogDump(true))
+	client := httpc.MustNewClient("https://httpbin.nbs.dev", httpc.LogDump(true))
 
 	// Do request
 	resp, _, err := client.DoRequest(context.Background(), "HEAD", "/")
@@ -241,3 +243,101 @@ func TestOverrideTransporter(t *testing.T) {
 		return
 	}
 }
+
+func TestInvalidBaseUrl(t *testing.T) {
+	for _, u := range []string{"", "api.nbs.dev", "ftp://api.nbs.dev", "https://api.nbs.dev?key=1", "://"} {
+		_, err := httpc.NewClient(u)
+		if err == nil {
+			t.Errorf("unexpected condition: invalid base url is accepted. BaseUrl = %s", u)
+		}
+	}
+}
+
+func TestUrlJoin(t *testing.T) {
+	var actual string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		actual = r.URL.RequestURI()
+	}))
+	defer srv.Close()
+
+	cases := []struct {
+		baseUrl  string
+		endpoint string
+		args     []httpc.SetRequestOptionFn
+		expected string
+	}{
+		{srv.URL + "/", "/v1", nil, "/v1"},
+		{srv.URL + "/api", "/v1/users", nil, "/api/v1/users"},
+		{srv.URL + "/api/", "v1/users", nil, "/api/v1/users"},
+		{srv.URL + "/api", "", nil, "/api"},
+		{srv.URL + "/api", "/projects:list", nil, "/api/projects:list"},
+		{srv.URL, "/search?q=go", []httpc.SetRequestOptionFn{httpc.AddQuery("page", "2")}, "/search?page=2&q=go"},
+	}
+	for _, tc := range cases {
+		uc := httpc.MustNewClient(tc.baseUrl)
+		_, _, err := uc.DoRequest(context.Background(), "GET", tc.endpoint, tc.args...)
+		if err != nil {
+			t.Errorf("unexpected error: %s", err)
+			continue
+		}
+		if actual != tc.expected {
+			t.Errorf("unexpected url. BaseUrl = %s, Endpoint = %s, Expected = %s, Actual = %s",
+				tc.baseUrl, tc.endpoint, tc.expected, actual)
+		}
+	}
+}
+
+func TestAbsoluteEndpointUrl(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer srv.Close()
+
+	_, _, err := c.DoRequest(context.Background(), "GET", srv.URL+"/get")
+	if err == nil {
+		t.Errorf("unexpected condition: absolute url is allowed by default")
+		return
+	}
+	ac := httpc.MustNewClient("https://httpbin.nbs.dev", httpc.AllowAbsoluteUrl())
+	resp, _, err := ac.DoRequest(context.Background(), "GET", srv.URL+"/get")
+	if err != nil {
+		t.Errorf("unexpected error: %s", err)
+		return
+	}
+	if resp.StatusCode != http.StatusOK {
+		t.Errorf("unexpected response status code. StatusCode = %d", resp.StatusCode)
+	}
+}
+
+func TestMultiValuedHeader(t *testing.T) {
+	var actual http.Header
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		actual = r.Header
+	}))
+	defer srv.Close()
+	hc := httpc.MustNewClient(srv.URL)
+
+	_, _, err := hc.DoRequest(context.Background(), "GET", "/",
+		httpc.AddHeader("Forwarded", "for=192.0.2.60", "forwarded", "for=198.51.100.17"),
+		httpc.AddHeader("X-Api-Key", "secret", "X-Trace", "1"),
+		httpc.SetHeader("x-trace", "2"),
+		httpc.DelHeader("x-api-key"),
+		httpc.AddHeader("X-UPPER", "hello"),
+		httpc.PreserveHeaderCase("X-UPPER"),
+	)
+	if err != nil {
+		t.Errorf("unexpected error: %s", err)
+		return
+	}
+	if v := actual.Values("Forwarded"); len(v) != 2 {
+		t.Errorf("unexpected Forwarded header values: %v", v)
+	}
+	if v := actual.Values("X-Trace"); len(v) != 1 || v[0] != "2" {
+		t.Errorf("unexpected X-Trace header values: %v", v)
+	}
+	if v := actual.Get("X-Api-Key"); v != "" {
+		t.Errorf("unexpected condition: X-Api-Key is not deleted. Value = %s", v)
+	}
+	// Server canonicalize received header keys, so only value can be asserted
+	if v := actual.Get("X-Upper"); v != "hello" {
+		t.Errorf("unexpected X-UPPER header value: %s", v)
+	}
+}
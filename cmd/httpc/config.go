@@ -0,0 +1,98 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// EnvConfigPath is the environment variable to set config file path
+const EnvConfigPath = "HTTPC_CONFIG"
+
+// Config is the config file that contains named environments, e.g.
+//
+//	{
+//	  "default": "local",
+//	  "environments": {
+//	    "local": {"baseUrl": "http://localhost:8080", "headers": {"X-Tenant": "a"}, "timeout": 5000},
+//	    "staging": {"baseUrl": "https://api.staging.example.com", "bearerToken": "${STAGING_TOKEN}", "retries": 2}
+//	  }
+//	}
+//
+// String values are expanded with environment variables, so secrets do not have to be written in config file
+type Config struct {
+	// Default is the environment that is used if -env flag is not set
+	Default      string                  `json:"default"`
+	Environments map[string]*Environment `json:"environments"`
+}
+
+// Environment is a named set of client behaviour
+type Environment struct {
+	BaseUrl string            `json:"baseUrl"`
+	Headers map[string]string `json:"headers"`
+	// Auth is basic auth credential in "username:password" format
+	Auth         string `json:"auth"`
+	BearerToken  string `json:"bearerToken"`
+	Timeout      int    `json:"timeout"`
+	Retries      int    `json:"retries"`
+	DisableHTTP2 bool   `json:"disableHttp2"`
+	LogDump      bool   `json:"logDump"`
+}
+
+// defaultConfigPath returns config path from HTTPC_CONFIG environment variable, or httpc/config.json in user config
+// directory
+func defaultConfigPath() string {
+	if p := os.Getenv(EnvConfigPath); p != "" {
+		return p
+	}
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(dir, "httpc", "config.json")
+}
+
+// loadConfig reads config file. If optional is true, missing config file returns empty config
+func loadConfig(path string, optional bool) (*Config, error) {
+	cfg := Config{Environments: make(map[string]*Environment)}
+	if path == "" {
+		return &cfg, nil
+	}
+	b, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) && optional {
+		return &cfg, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("httpc: Failed to read config. Path = %s, Error = %w", path, err)
+	}
+	err = json.Unmarshal(b, &cfg)
+	if err != nil {
+		return nil, fmt.Errorf("httpc: Failed to decode config. Path = %s, Error = %w", path, err)
+	}
+	return &cfg, nil
+}
+
+// Environment returns expanded environment by name, or default environment if name is empty
+func (c *Config) Environment(name string) (*Environment, error) {
+	if name == "" {
+		name = c.Default
+	}
+	if name == "" {
+		return &Environment{}, nil
+	}
+	e, ok := c.Environments[name]
+	if !ok {
+		return nil, fmt.Errorf("httpc: Environment is not found in config. Environment = %s", name)
+	}
+	r := *e
+	r.BaseUrl = os.ExpandEnv(r.BaseUrl)
+	r.Auth = os.ExpandEnv(r.Auth)
+	r.BearerToken = os.ExpandEnv(r.BearerToken)
+	r.Headers = make(map[string]string, len(e.Headers))
+	for k, v := range e.Headers {
+		r.Headers[k] = os.ExpandEnv(v)
+	}
+	return &r, nil
+}
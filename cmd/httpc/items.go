@@ -0,0 +1,100 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// itemType is the type of request item
+type itemType int8
+
+const (
+	// itemHeader is "Header:Value"
+	itemHeader itemType = iota
+	// itemQuery is "name==value"
+	itemQuery
+	// itemField is "name=value", a string field of JSON or form body
+	itemField
+	// itemRawJson is "name:=json", a raw JSON field of JSON body
+	itemRawJson
+)
+
+// itemSeparators are ordered by priority if separators start at the same position
+var itemSeparators = []struct {
+	sep string
+	t   itemType
+}{
+	{":=", itemRawJson},
+	{"==", itemQuery},
+	{"=", itemField},
+	{":", itemHeader},
+}
+
+// item is request item argument
+type item struct {
+	t     itemType
+	key   string
+	value string
+}
+
+// parseItem parse request item by the first separator in arg
+func parseItem(arg string) (item, error) {
+	for i := 0; i < len(arg); i++ {
+		for _, s := range itemSeparators {
+			if !strings.HasPrefix(arg[i:], s.sep) {
+				continue
+			}
+			if i == 0 {
+				return item{}, fmt.Errorf("httpc: Invalid request item, key is empty. Item = %s", arg)
+			}
+			it := item{t: s.t, key: arg[:i], value: arg[i+len(s.sep):]}
+			if it.t == itemHeader {
+				it.value = strings.TrimSpace(it.value)
+			}
+			return it, nil
+		}
+	}
+	return item{}, fmt.Errorf("httpc: Invalid request item. Item = %s", arg)
+}
+
+// jsonBody compose JSON body from string and raw JSON fields
+func jsonBody(items []item) (map[string]interface{}, error) {
+	var body map[string]interface{}
+	for _, it := range items {
+		var v interface{}
+		switch it.t {
+		case itemField:
+			v = it.value
+		case itemRawJson:
+			if err := json.Unmarshal([]byte(it.value), &v); err != nil {
+				return nil, fmt.Errorf("httpc: Invalid raw JSON field. Field = %s, Error = %w", it.key, err)
+			}
+		default:
+			continue
+		}
+		if body == nil {
+			body = make(map[string]interface{})
+		}
+		body[it.key] = v
+	}
+	return body, nil
+}
+
+// formBody compose form body from string fields. Raw JSON fields are not allowed
+func formBody(items []item) (url.Values, error) {
+	var body url.Values
+	for _, it := range items {
+		switch it.t {
+		case itemField:
+			if body == nil {
+				body = make(url.Values)
+			}
+			body.Add(it.key, it.value)
+		case itemRawJson:
+			return nil, fmt.Errorf("httpc: Raw JSON field is not allowed in form body. Field = %s", it.key)
+		}
+	}
+	return body, nil
+}
@@ -0,0 +1,366 @@
+// Command httpc is a command-line HTTP client built on httpc library, so requests are sent with the same client
+// behaviour as services that use the library.
+//
+// Usage:
+//
+//	httpc [flags] [METHOD] URL [ITEM...]
+//
+// Request items:
+//
+//	Header:Value   Request header
+//	name==value    Query parameter
+//	name=value     String field of JSON body, or form body if -form is set
+//	name:=json     Raw JSON field of JSON body, e.g. count:=1, tags:='["a","b"]'
+//
+// URL that starts with "/" is relative to base url of environment. URL that starts with ":" is a shorthand for
+// localhost, e.g. :8080/users. Method is GET if request has no body fields, otherwise POST.
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"github.com/nbs-go/httpc"
+	"github.com/nbs-go/nlogger/v2"
+	"github.com/nbs-go/nlogger/v2/level"
+	logOption "github.com/nbs-go/nlogger/v2/option"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"os/signal"
+	"strings"
+	"time"
+)
+
+// Exit codes
+const (
+	exitOk    = 0
+	exitError = 1
+	exitUsage = 2
+)
+
+// retryBackoff is the delay before the first retry, doubled on each retry
+var retryBackoff = 200 * time.Millisecond
+
+func main() {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	code := run(ctx, os.Args[1:], os.Stdout, os.Stderr)
+	stop()
+	os.Exit(code)
+}
+
+// flags is the command-line flags
+type flags struct {
+	env         string
+	config      string
+	form        bool
+	auth        string
+	bearer      string
+	timeout     int
+	retries     int
+	retryAll    bool
+	http1       bool
+	verbose     bool
+	bodyOnly    bool
+	color       string
+	checkStatus bool
+}
+
+func run(ctx context.Context, args []string, stdout io.Writer, stderr io.Writer) int {
+	// Parse flags
+	var f flags
+	fs := flag.NewFlagSet("httpc", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	fs.StringVar(&f.env, "env", "", "Named environment in config file")
+	fs.StringVar(&f.config, "config", "", "Config file path. Default is $"+EnvConfigPath+" or httpc/config.json in user config directory")
+	fs.BoolVar(&f.form, "form", false, "Send body fields as url encoded form instead of JSON")
+	fs.StringVar(&f.auth, "auth", "", "Basic auth credential in username:password format")
+	fs.StringVar(&f.bearer, "bearer", "", "Bearer token")
+	fs.IntVar(&f.timeout, "timeout", 0, "Request timeout in milliseconds")
+	fs.IntVar(&f.retries, "retries", -1, "Number of retries on network error or 5xx response of idempotent method")
+	fs.BoolVar(&f.retryAll, "retry-all", false, "Retry 5xx response of non-idempotent method, e.g. POST")
+	fs.BoolVar(&f.http1, "http1", false, "Disable HTTP/2")
+	fs.BoolVar(&f.verbose, "v", false, "Log request and response dump to stderr")
+	fs.BoolVar(&f.bodyOnly, "b", false, "Print response body only")
+	fs.StringVar(&f.color, "color", "auto", "Colorize output: auto, always or never")
+	fs.BoolVar(&f.checkStatus, "check-status", false, "Exit with 3, 4 or 5 if response status is 3xx, 4xx or 5xx")
+	fs.Usage = func() {
+		_, _ = fmt.Fprintln(stderr, "Usage: httpc [flags] [METHOD] URL [ITEM...]")
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		return exitUsage
+	}
+	if err := f.validate(fs.Args()); err != nil {
+		_, _ = fmt.Fprintln(stderr, err)
+		fs.Usage()
+		return exitUsage
+	}
+	resp, body, err := f.do(ctx, fs.Args(), stderr)
+	if err != nil {
+		_, _ = fmt.Fprintln(stderr, err)
+		return exitError
+	}
+	p := printer{out: stdout, color: f.useColor(stdout), bodyOnly: f.bodyOnly}
+	p.print(resp, body)
+	if f.checkStatus && resp.StatusCode >= 300 {
+		return resp.StatusCode / 100
+	}
+	return exitOk
+}
+
+func (f *flags) validate(args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("httpc: URL is required")
+	}
+	if f.color != "auto" && f.color != "always" && f.color != "never" {
+		return fmt.Errorf("httpc: Invalid color flag. Color = %s", f.color)
+	}
+	return nil
+}
+
+// do sends request and returns response with body
+func (f *flags) do(ctx context.Context, args []string, stderr io.Writer) (*http.Response, []byte, error) {
+	// Load environment
+	cfgPath, optional := f.config, false
+	if cfgPath == "" {
+		cfgPath, optional = defaultConfigPath(), true
+	}
+	cfg, err := loadConfig(cfgPath, optional)
+	if err != nil {
+		return nil, nil, err
+	}
+	env, err := cfg.Environment(f.env)
+	if err != nil {
+		return nil, nil, err
+	}
+	// Parse method, url and items
+	var method string
+	if len(args) > 1 && isMethod(args[0]) {
+		method, args = args[0], args[1:]
+	}
+	baseUrl, endpointPath, err := resolveUrl(env.BaseUrl, args[0])
+	if err != nil {
+		return nil, nil, err
+	}
+	items := make([]item, 0, len(args)-1)
+	for _, arg := range args[1:] {
+		it, iErr := parseItem(arg)
+		if iErr != nil {
+			return nil, nil, iErr
+		}
+		items = append(items, it)
+	}
+	// Init client. Flags take precedence over environment
+	logDump := f.verbose || env.LogDump
+	lv := level.Warn
+	if logDump {
+		lv = level.Debug
+	}
+	nlogger.Register(nlogger.NewStdLogger(nlogger.NewStdLogPrinter(stderr, 0), logOption.Level(lv)))
+	opts := []httpc.SetClientOptionsFn{httpc.AllowAbsoluteUrl(), httpc.LogDump(logDump)}
+	if f.http1 || env.DisableHTTP2 {
+		opts = append(opts, httpc.DisableHTTP2())
+	}
+	client, err := httpc.NewClient(baseUrl, opts...)
+	if err != nil {
+		return nil, nil, err
+	}
+	// Compose request
+	rr, err := f.newRequest(client, env, method, endpointPath, items)
+	if err != nil {
+		return nil, nil, err
+	}
+	retries := env.Retries
+	if f.retries >= 0 {
+		retries = f.retries
+	}
+	result, err := execute(ctx, rr, retries, f.retryAll)
+	if err != nil {
+		return nil, nil, err
+	}
+	return result.Response, result.Body, nil
+}
+
+// newRequest creates REST request from environment, flags and request items
+func (f *flags) newRequest(client *httpc.Client, env *Environment, method string, endpointPath string,
+	items []item) (*httpc.RESTRequest, error) {
+	// Compose body
+	var bodyOpt httpc.SetRequestOptionFn
+	if f.form {
+		form, err := formBody(items)
+		if err != nil {
+			return nil, err
+		}
+		if form != nil {
+			bodyOpt = httpc.SetUrlEncodedFormBody(form)
+		}
+	} else {
+		body, err := jsonBody(items)
+		if err != nil {
+			return nil, err
+		}
+		if body != nil {
+			bodyOpt = httpc.SetJsonBody(body)
+		}
+	}
+	if method == "" {
+		method = httpc.MethodGet
+		if bodyOpt != nil {
+			method = httpc.MethodPost
+		}
+	}
+	rr := httpc.NewRESTRequest(client, method, endpointPath)
+	if bodyOpt != nil {
+		rr.AddOption(bodyOpt)
+	}
+	// Set header, header items replace environment headers with the same key
+	for k, v := range env.Headers {
+		rr.SetHeader(k, v)
+	}
+	set := make(map[string]bool)
+	for _, it := range items {
+		switch it.t {
+		case itemHeader:
+			k := http.CanonicalHeaderKey(it.key)
+			if set[k] {
+				rr.AddHeader(it.key, it.value)
+			} else {
+				rr.SetHeader(it.key, it.value)
+				set[k] = true
+			}
+		case itemQuery:
+			rr.AddQuery(it.key, it.value)
+		}
+	}
+	// Set auth. Authorization header item takes precedence over environment auth
+	switch {
+	case f.auth != "":
+		rr.BasicAuth(splitCredential(f.auth))
+	case f.bearer != "":
+		rr.BearerToken(f.bearer)
+	case set[httpc.HeaderAuthorization]:
+	case env.Auth != "":
+		rr.BasicAuth(splitCredential(env.Auth))
+	case env.BearerToken != "":
+		rr.BearerToken(env.BearerToken)
+	}
+	// Set timeout
+	timeout := env.Timeout
+	if f.timeout > 0 {
+		timeout = f.timeout
+	}
+	if timeout > 0 {
+		rr.AddOption(httpc.Timeout(timeout))
+	}
+	return rr, nil
+}
+
+func (f *flags) useColor(w io.Writer) bool {
+	switch f.color {
+	case "always":
+		return true
+	case "never":
+		return false
+	}
+	if _, ok := os.LookupEnv("NO_COLOR"); ok {
+		return false
+	}
+	file, ok := w.(*os.File)
+	if !ok {
+		return false
+	}
+	fi, err := file.Stat()
+	return err == nil && fi.Mode()&os.ModeCharDevice != 0
+}
+
+// execute sends request and retries on network error or 5xx response with exponential backoff. 5xx response of
+// non-idempotent method is only retried if retryAll is set. The library does not retry requests, so retry is done here
+func execute(ctx context.Context, rr *httpc.RESTRequest, retries int, retryAll bool) (*httpc.Result, error) {
+	delay := retryBackoff
+	for i := 0; ; i++ {
+		result, err := rr.Execute(ctx)
+		if i >= retries || ctx.Err() != nil || !shouldRetry(result, err, retryAll) {
+			return result, err
+		}
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(delay):
+		}
+		delay *= 2
+	}
+}
+
+// shouldRetry returns true if request failed with network error, or responded with 5xx status
+func shouldRetry(result *httpc.Result, err error, retryAll bool) bool {
+	if err != nil {
+		return isNetworkError(err)
+	}
+	if result.StatusCode() < 500 {
+		return false
+	}
+	return retryAll || isIdempotent(result.Response.Request.Method)
+}
+
+// isNetworkError returns true if error is returned by transport when sending request. Errors that are returned
+// before request is sent, e.g. invalid url or failed authentication, are not retried
+func isNetworkError(err error) bool {
+	uErr, ok := err.(*url.Error)
+	if !ok {
+		return false
+	}
+	var nErr net.Error
+	return errors.As(uErr.Err, &nErr) || errors.Is(uErr.Err, io.EOF) || errors.Is(uErr.Err, io.ErrUnexpectedEOF)
+}
+
+// isIdempotent returns true if request with method could be sent more than once with the same effect
+func isIdempotent(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodTrace, http.MethodPut, http.MethodDelete:
+		return true
+	}
+	return false
+}
+
+// resolveUrl returns client base url and endpoint path of url argument
+func resolveUrl(baseUrl string, arg string) (string, string, error) {
+	switch {
+	case strings.Contains(arg, "://"):
+	case strings.HasPrefix(arg, ":"):
+		arg = "http://localhost" + arg
+	case baseUrl != "":
+		// Relative to base url of environment
+		return baseUrl, arg, nil
+	default:
+		arg = "http://" + arg
+	}
+	u, err := url.Parse(arg)
+	if err != nil {
+		return "", "", fmt.Errorf("httpc: Invalid url. Url = %s, Error = %w", arg, err)
+	}
+	if baseUrl == "" {
+		baseUrl = u.Scheme + "://" + u.Host
+	}
+	return baseUrl, u.String(), nil
+}
+
+// isMethod returns true if arg is an upper case http method, e.g. GET
+func isMethod(arg string) bool {
+	for _, c := range arg {
+		if c < 'A' || c > 'Z' {
+			return false
+		}
+	}
+	return arg != ""
+}
+
+func splitCredential(s string) (string, string) {
+	if i := strings.IndexByte(s, ':'); i >= 0 {
+		return s[:i], s[i+1:]
+	}
+	return s, ""
+}
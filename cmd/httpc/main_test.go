@@ -0,0 +1,228 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestMain(m *testing.M) {
+	retryBackoff = time.Millisecond
+	// Ignore config in user config directory
+	_ = os.Setenv(EnvConfigPath, filepath.Join(os.TempDir(), "httpc-test-missing.json"))
+	os.Exit(m.Run())
+}
+
+// echoServer responds with JSON that describes the request
+func echoServer() *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		u, p, _ := r.BasicAuth()
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{
+			"method":        r.Method,
+			"url":           r.URL.String(),
+			"contentType":   r.Header.Get("Content-Type"),
+			"tenant":        r.Header.Values("X-Tenant"),
+			"authorization": r.Header.Get("Authorization"),
+			"user":          u + ":" + p,
+			"body":          string(body),
+		})
+	}))
+}
+
+func runCommand(args ...string) (int, map[string]interface{}, string) {
+	var stdout, stderr bytes.Buffer
+	code := run(context.Background(), args, &stdout, &stderr)
+	var result map[string]interface{}
+	_ = json.Unmarshal(stdout.Bytes(), &result)
+	return code, result, stderr.String()
+}
+
+func TestParseItem(t *testing.T) {
+	cases := map[string]item{
+		"X-Tenant: a":     {t: itemHeader, key: "X-Tenant", value: "a"},
+		"q==a=b":          {t: itemQuery, key: "q", value: "a=b"},
+		"name=john:doe":   {t: itemField, key: "name", value: "john:doe"},
+		`tags:=["a","b"]`: {t: itemRawJson, key: "tags", value: `["a","b"]`},
+		"url=http://x":    {t: itemField, key: "url", value: "http://x"},
+	}
+	for arg, expected := range cases {
+		it, err := parseItem(arg)
+		if err != nil || it != expected {
+			t.Errorf("unexpected item. Arg = %s, Item = %+v, Error = %v", arg, it, err)
+		}
+	}
+	for _, arg := range []string{"name", "=value"} {
+		if _, err := parseItem(arg); err == nil {
+			t.Errorf("expected error is not returned. Arg = %s", arg)
+		}
+	}
+}
+
+func TestRunJsonBody(t *testing.T) {
+	srv := echoServer()
+	defer srv.Close()
+
+	code, result, stderr := runCommand("-b", "-auth", "john:s3cret", srv.URL+"/users", "X-Tenant:a", "q==1",
+		"name=john", "age:=30", "tags:=[\"a\"]")
+	if code != exitOk {
+		t.Errorf("unexpected exit code: %d, Stderr = %s", code, stderr)
+		return
+	}
+	if result["method"] != "POST" || result["url"] != "/users?q=1" || result["contentType"] != "application/json" ||
+		result["user"] != "john:s3cret" || result["body"] != `{"age":30,"name":"john","tags":["a"]}` {
+		t.Errorf("unexpected request: %v", result)
+	}
+}
+
+func TestRunFormBody(t *testing.T) {
+	srv := echoServer()
+	defer srv.Close()
+
+	code, result, stderr := runCommand("-b", "-form", "PUT", srv.URL+"/login", "user=john", "user=jane")
+	if code != exitOk {
+		t.Errorf("unexpected exit code: %d, Stderr = %s", code, stderr)
+		return
+	}
+	if result["method"] != "PUT" || result["contentType"] != "application/x-www-form-urlencoded" ||
+		result["body"] != "user=john&user=jane" {
+		t.Errorf("unexpected request: %v", result)
+	}
+
+	code, _, _ = runCommand("-form", srv.URL, "count:=1")
+	if code != exitError {
+		t.Errorf("unexpected exit code: %d", code)
+	}
+}
+
+func TestRunEnvironment(t *testing.T) {
+	srv := echoServer()
+	defer srv.Close()
+	_ = os.Setenv("HTTPC_TEST_TOKEN", "s3cret-token")
+	defer func() { _ = os.Unsetenv("HTTPC_TEST_TOKEN") }()
+	cfg := filepath.Join(t.TempDir(), "config.json")
+	_ = os.WriteFile(cfg, []byte(`{
+		"default": "local",
+		"environments": {
+			"local": {"baseUrl": "`+srv.URL+`/v1", "headers": {"X-Tenant": "a"}, "bearerToken": "${HTTPC_TEST_TOKEN}"},
+			"other": {"baseUrl": "http://127.0.0.1:1"}
+		}
+	}`), 0600)
+
+	code, result, stderr := runCommand("-b", "-config", cfg, "/users/1", "X-Tenant:b", "X-Tenant:c")
+	if code != exitOk {
+		t.Errorf("unexpected exit code: %d, Stderr = %s", code, stderr)
+		return
+	}
+	tenant, _ := json.Marshal(result["tenant"])
+	if result["method"] != "GET" || result["url"] != "/v1/users/1" || string(tenant) != `["b","c"]` ||
+		result["authorization"] != "Bearer s3cret-token" {
+		t.Errorf("unexpected request: %v", result)
+	}
+
+	// Assert Authorization header item is not overwritten by environment auth
+	code, result, stderr = runCommand("-b", "-config", cfg, "/users/1", "Authorization:Bearer other")
+	if code != exitOk || result["authorization"] != "Bearer other" {
+		t.Errorf("unexpected result. Code = %d, Request = %v, Stderr = %s", code, result, stderr)
+	}
+
+	code, _, stderr = runCommand("-config", cfg, "-env", "missing", "/users/1")
+	if code != exitError || !strings.Contains(stderr, "Environment = missing") {
+		t.Errorf("unexpected exit code: %d, Stderr = %s", code, stderr)
+	}
+}
+
+func TestRunRetries(t *testing.T) {
+	var count int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&count, 1) < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		_, _ = w.Write([]byte("ok"))
+	}))
+	defer srv.Close()
+
+	var stdout, stderr bytes.Buffer
+	code := run(context.Background(), []string{"-retries", "2", "-check-status", srv.URL}, &stdout, &stderr)
+	if code != exitOk || count != 3 || !strings.HasSuffix(stdout.String(), "\n\nok\n") {
+		t.Errorf("unexpected result. Code = %d, Count = %d, Stdout = %s", code, count, stdout.String())
+	}
+
+	atomic.StoreInt32(&count, 0)
+	code = run(context.Background(), []string{"-retries", "1", "-check-status", srv.URL}, &stdout, &stderr)
+	if code != 5 || count != 2 {
+		t.Errorf("unexpected result. Code = %d, Count = %d", code, count)
+	}
+
+	// Assert non-idempotent method is not retried on 5xx, unless retry all is set
+	atomic.StoreInt32(&count, 0)
+	code = run(context.Background(), []string{"-retries", "2", "-check-status", "POST", srv.URL, "name=john"},
+		&stdout, &stderr)
+	if code != 5 || count != 1 {
+		t.Errorf("unexpected result. Code = %d, Count = %d", code, count)
+	}
+	atomic.StoreInt32(&count, 0)
+	code = run(context.Background(), []string{"-retries", "2", "-retry-all", "-check-status", "POST", srv.URL,
+		"name=john"}, &stdout, &stderr)
+	if code != exitOk || count != 3 {
+		t.Errorf("unexpected result. Code = %d, Count = %d", code, count)
+	}
+}
+
+func TestRunRetriesNetworkError(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	u := srv.URL
+	srv.Close()
+
+	// Assert network error is retried
+	var stdout, stderr bytes.Buffer
+	start := time.Now()
+	code := run(context.Background(), []string{"-retries", "2", "POST", u}, &stdout, &stderr)
+	if code != exitError || time.Since(start) < 3*retryBackoff {
+		t.Errorf("unexpected result. Code = %d, Elapsed = %s", code, time.Since(start))
+	}
+
+	// Assert error that is returned before request is sent is not retried
+	if shouldRetry(nil, errors.New("httpc: Failed to authenticate request"), true) {
+		t.Errorf("unexpected condition: non-network error is retried")
+	}
+}
+
+func TestRunVerbose(t *testing.T) {
+	srv := echoServer()
+	defer srv.Close()
+
+	code, _, stderr := runCommand("-v", "-http1", srv.URL, "name=john")
+	if code != exitOk || !strings.Contains(stderr, `{"name":"john"}`) {
+		t.Errorf("unexpected result. Code = %d, Stderr = %s", code, stderr)
+	}
+}
+
+func TestPrintColorizedJson(t *testing.T) {
+	resp := &http.Response{
+		Proto:  "HTTP/1.1",
+		Status: "200 OK",
+		Header: http.Header{"Content-Type": {"application/json"}},
+	}
+	var out bytes.Buffer
+	p := printer{out: &out, color: true}
+	p.print(resp, []byte(`{"a":"x\"y","b":[1,true,null]}`))
+	expected := "\x1b[1mHTTP/1.1 200 OK\x1b[0m\n\x1b[36mContent-Type\x1b[0m: application/json\n\n" +
+		"{\n    \x1b[34m\"a\"\x1b[0m: \x1b[32m\"x\\\"y\"\x1b[0m,\n    \x1b[34m\"b\"\x1b[0m: [\n" +
+		"        \x1b[33m1\x1b[0m,\n        \x1b[36mtrue\x1b[0m,\n        \x1b[36mnull\x1b[0m\n    ]\n}\n"
+	if out.String() != expected {
+		t.Errorf("unexpected output.\nExpected = %q\nActual   = %q", expected, out.String())
+	}
+}
@@ -0,0 +1,129 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"sort"
+	"strings"
+)
+
+// ANSI colors
+const (
+	colorReset   = "\x1b[0m"
+	colorKey     = "\x1b[34m"
+	colorString  = "\x1b[32m"
+	colorNumber  = "\x1b[33m"
+	colorLiteral = "\x1b[36m"
+	colorHeader  = "\x1b[36m"
+	colorStatus  = "\x1b[1m"
+)
+
+// printer writes response to output
+type printer struct {
+	out      io.Writer
+	color    bool
+	bodyOnly bool
+}
+
+// print writes status line, headers and body of response. JSON body is indented and colorized
+func (p *printer) print(resp *http.Response, body []byte) {
+	if !p.bodyOnly {
+		p.printf(colorStatus, "%s %s", resp.Proto, resp.Status)
+		_, _ = io.WriteString(p.out, "\n")
+		keys := make([]string, 0, len(resp.Header))
+		for k := range resp.Header {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			for _, v := range resp.Header[k] {
+				p.printf(colorHeader, "%s", k)
+				_, _ = fmt.Fprintf(p.out, ": %s\n", v)
+			}
+		}
+		_, _ = io.WriteString(p.out, "\n")
+	}
+	if len(body) == 0 {
+		return
+	}
+	if isJson(resp.Header.Get("Content-Type")) {
+		var b bytes.Buffer
+		if err := json.Indent(&b, body, "", "    "); err == nil {
+			body = b.Bytes()
+			if p.color {
+				body = colorizeJson(body)
+			}
+		}
+	}
+	_, _ = p.out.Write(body)
+	if body[len(body)-1] != '\n' {
+		_, _ = io.WriteString(p.out, "\n")
+	}
+}
+
+func (p *printer) printf(color string, format string, args ...interface{}) {
+	if p.color {
+		_, _ = io.WriteString(p.out, color)
+		defer func() { _, _ = io.WriteString(p.out, colorReset) }()
+	}
+	_, _ = fmt.Fprintf(p.out, format, args...)
+}
+
+// isJson returns true if content type is application/json or has +json suffix
+func isJson(contentType string) bool {
+	mt, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return false
+	}
+	return mt == "application/json" || strings.HasSuffix(mt, "+json")
+}
+
+// colorizeJson adds ANSI colors to valid JSON
+func colorizeJson(b []byte) []byte {
+	var out bytes.Buffer
+	for i := 0; i < len(b); {
+		c := b[i]
+		switch {
+		case c == '"':
+			// Find closing quote
+			end := i + 1
+			for end < len(b) && b[end] != '"' {
+				if b[end] == '\\' {
+					end++
+				}
+				end++
+			}
+			end++
+			// String followed by colon is an object key
+			color := colorString
+			if j := bytes.IndexFunc(b[end:], func(r rune) bool { return r != ' ' }); j >= 0 && b[end+j] == ':' {
+				color = colorKey
+			}
+			out.WriteString(color)
+			out.Write(b[i:end])
+			out.WriteString(colorReset)
+			i = end
+		case c == '-' || c >= '0' && c <= '9' || c == 't' || c == 'f' || c == 'n':
+			end := i + 1
+			for end < len(b) && bytes.IndexByte([]byte(",]} \n"), b[end]) < 0 {
+				end++
+			}
+			color := colorNumber
+			if c == 't' || c == 'f' || c == 'n' {
+				color = colorLiteral
+			}
+			out.WriteString(color)
+			out.Write(b[i:end])
+			out.WriteString(colorReset)
+			i = end
+		default:
+			out.WriteByte(c)
+			i++
+		}
+	}
+	return out.Bytes()
+}
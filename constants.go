@@ -1,22 +1,22 @@
 package httpc
 
 const (
-	HeaderContentType = "Content-Type"
+	HeaderContentType   = "Content-Type"
+	HeaderAuthorization = "Authorization"
+	HeaderRequestId     = "X-Request-Id"
 )
 
 const (
-	MimeTypeJson           = "application/json"
-	MimeTypeUrlEncodedForm = "application/x-www-form-urlencoded"
+	TokenTypeBearer = "Bearer"
 )
 
-type ContextKey int8
-
 const (
-	ContextRequestId = iota + 1
+	MimeTypeJson           = "application/json"
+	MimeTypeUrlEncodedForm = "application/x-www-form-urlencoded"
 )
 
-type Instrumentation int8
+type ContextKey int8
 
 const (
-	InstrumentationOpenTelemetry = iota + 1
+	ContextRequestId ContextKey = iota + 1
 )
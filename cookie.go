@@ -0,0 +1,178 @@
+package httpc
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"golang.org/x/net/publicsuffix"
+	"io"
+	"net/http"
+	"net/http/cookiejar"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+// cloneWithCookies returns a copy of req with Cookie header that is sent by http.Client. If req is not sent yet,
+// cookies from jar are added to the copy. Sent request already has cookies from jar in its header
+func (c *Client) cloneWithCookies(ctx context.Context, req *http.Request, sent bool) *http.Request {
+	dr := req.Clone(ctx)
+	if sent || c.httpClient.Jar == nil {
+		return dr
+	}
+	for _, ck := range c.httpClient.Jar.Cookies(req.URL) {
+		dr.AddCookie(ck)
+	}
+	return dr
+}
+
+// NewCookieJar creates in-memory cookie jar that is aware of public suffixes, so a site could not set cookies for
+// domains such as "co.uk". Cookies could be persisted using Save and Load
+func NewCookieJar() *Jar {
+	// cookiejar.New never returns error
+	jar, _ := cookiejar.New(&cookiejar.Options{PublicSuffixList: publicsuffix.List})
+	return &Jar{
+		jar:     jar,
+		entries: make(map[string]*jarEntry),
+	}
+}
+
+// Jar is an in-memory http.CookieJar that keep track of stored cookies, so it could be persisted
+type Jar struct {
+	jar     *cookiejar.Jar
+	mu      sync.Mutex
+	entries map[string]*jarEntry
+}
+
+type jarEntry struct {
+	Url    string       `json:"url"`
+	Cookie *http.Cookie `json:"cookie"`
+}
+
+func (j *Jar) SetCookies(u *url.URL, cookies []*http.Cookie) {
+	j.jar.SetCookies(u, cookies)
+	// Track cookies for persistence
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	now := time.Now()
+	for _, c := range cookies {
+		domain := strings.ToLower(strings.TrimPrefix(c.Domain, "."))
+		if domain == "" {
+			domain = strings.ToLower(u.Hostname())
+		}
+		cc := *c
+		if cc.Path == "" || cc.Path[0] != '/' {
+			cc.Path = defaultCookiePath(u.Path)
+		}
+		key := fmt.Sprintf("%s;%s;%s", domain, cc.Path, cc.Name)
+		// Remove deleted cookies
+		if c.MaxAge < 0 || (!c.Expires.IsZero() && !c.Expires.After(now)) {
+			delete(j.entries, key)
+			continue
+		}
+		// Skip cookies that are rejected by jar, e.g. cookies for public suffix or mismatched domain
+		if !j.accepted(u, domain, &cc) {
+			continue
+		}
+		if cc.MaxAge > 0 {
+			cc.Expires = now.Add(time.Duration(cc.MaxAge) * time.Second)
+			cc.MaxAge = 0
+		}
+		cc.Raw = ""
+		cc.Unparsed = nil
+		j.entries[key] = &jarEntry{
+			Url:    (&url.URL{Scheme: u.Scheme, Host: u.Host, Path: u.Path}).String(),
+			Cookie: &cc,
+		}
+	}
+}
+
+// accepted returns true if cookie is stored in jar, by querying cookies of its domain and path
+func (j *Jar) accepted(u *url.URL, domain string, c *http.Cookie) bool {
+	q := url.URL{Scheme: u.Scheme, Host: domain, Path: c.Path}
+	if c.Secure {
+		q.Scheme = "https"
+	}
+	for _, sc := range j.jar.Cookies(&q) {
+		if sc.Name == c.Name && sc.Value == c.Value {
+			return true
+		}
+	}
+	return false
+}
+
+// defaultCookiePath returns default path of cookie as defined in RFC 6265 section 5.1.4
+func defaultCookiePath(p string) string {
+	if p == "" || p[0] != '/' {
+		return "/"
+	}
+	i := strings.LastIndex(p, "/")
+	if i == 0 {
+		return "/"
+	}
+	return p[:i]
+}
+
+func (j *Jar) Cookies(u *url.URL) []*http.Cookie {
+	return j.jar.Cookies(u)
+}
+
+// Save writes persistent and session cookies that have not expired as JSON to w
+func (j *Jar) Save(w io.Writer) error {
+	j.mu.Lock()
+	entries := make([]*jarEntry, 0, len(j.entries))
+	now := time.Now()
+	for _, e := range j.entries {
+		if !e.Cookie.Expires.IsZero() && !e.Cookie.Expires.After(now) {
+			continue
+		}
+		entries = append(entries, e)
+	}
+	j.mu.Unlock()
+	return json.NewEncoder(w).Encode(entries)
+}
+
+// Load reads cookies written by Save from r and store it to jar
+func (j *Jar) Load(r io.Reader) error {
+	var entries []*jarEntry
+	err := json.NewDecoder(r).Decode(&entries)
+	if err != nil {
+		return fmt.Errorf("httpc: Failed to decode cookies. Error = %w", err)
+	}
+	for _, e := range entries {
+		u, pErr := url.Parse(e.Url)
+		if pErr != nil || e.Cookie == nil {
+			continue
+		}
+		j.SetCookies(u, []*http.Cookie{e.Cookie})
+	}
+	return nil
+}
+
+// SaveFile writes cookies to file, file is created with 0600 permission
+func (j *Jar) SaveFile(path string) error {
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0600)
+	if err != nil {
+		return err
+	}
+	err = j.Save(f)
+	if cErr := f.Close(); err == nil {
+		err = cErr
+	}
+	return err
+}
+
+// LoadFile reads cookies from file. Missing file is not an error
+func (j *Jar) LoadFile(path string) error {
+	f, err := os.Open(path)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	defer func() { _ = f.Close() }()
+	return j.Load(f)
+}
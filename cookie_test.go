@@ -0,0 +1,115 @@
+package httpc_test
+
+import (
+	"bytes"
+	"context"
+	"github.com/nbs-go/httpc"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"path/filepath"
+	"testing"
+)
+
+func newSessionServer() *httptest.Server {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/login", func(w http.ResponseWriter, r *http.Request) {
+		http.SetCookie(w, &http.Cookie{Name: "session", Value: "s3cret", Path: "/", MaxAge: 3600, HttpOnly: true})
+	})
+	mux.HandleFunc("/me", func(w http.ResponseWriter, r *http.Request) {
+		ck, err := r.Cookie("session")
+		if err != nil || ck.Value != "s3cret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		if ck, err = r.Cookie("locale"); err == nil {
+			_, _ = w.Write([]byte(ck.Value))
+		}
+	})
+	return httptest.NewServer(mux)
+}
+
+func TestCookieJarSession(t *testing.T) {
+	srv := newSessionServer()
+	defer srv.Close()
+	jar := httpc.NewCookieJar()
+	sc := httpc.MustNewClient(srv.URL, httpc.CookieJar(jar), httpc.LogDump(true))
+
+	_, _, err := sc.DoRequest(context.Background(), "POST", "/login")
+	if err != nil {
+		t.Errorf("unexpected error: %s", err)
+		return
+	}
+	resp, body, err := sc.DoRequest(context.Background(), "GET", "/me",
+		httpc.AddCookie(&http.Cookie{Name: "locale", Value: "id"}))
+	if err != nil {
+		t.Errorf("unexpected error: %s", err)
+		return
+	}
+	if resp.StatusCode != http.StatusOK || string(body) != "id" {
+		t.Errorf("unexpected response. Status = %d, Body = %s", resp.StatusCode, body)
+		return
+	}
+
+	// Assert cookies are persisted
+	path := filepath.Join(t.TempDir(), "cookies.json")
+	if err = jar.SaveFile(path); err != nil {
+		t.Errorf("unexpected error: %s", err)
+		return
+	}
+	loaded := httpc.NewCookieJar()
+	if err = loaded.LoadFile(path); err != nil {
+		t.Errorf("unexpected error: %s", err)
+		return
+	}
+	lc := httpc.MustNewClient(srv.URL, httpc.CookieJar(loaded))
+	resp, _, err = lc.DoRequest(context.Background(), "GET", "/me")
+	if err != nil {
+		t.Errorf("unexpected error: %s", err)
+		return
+	}
+	if resp.StatusCode != http.StatusOK {
+		t.Errorf("unexpected response status code. StatusCode = %d", resp.StatusCode)
+	}
+}
+
+func TestCookieJarPublicSuffix(t *testing.T) {
+	jar := httpc.NewCookieJar()
+	u, _ := url.Parse("https://www.example.co.uk")
+	jar.SetCookies(u, []*http.Cookie{{Name: "tracker", Value: "1", Domain: "co.uk"}})
+	other, _ := url.Parse("https://other.co.uk")
+	if cookies := jar.Cookies(other); len(cookies) != 0 {
+		t.Errorf("unexpected condition: cookie is set for public suffix domain. Cookies = %v", cookies)
+	}
+}
+
+func TestCookieJarSaveAccepted(t *testing.T) {
+	jar := httpc.NewCookieJar()
+	u, _ := url.Parse("https://www.example.co.uk/account/login")
+	jar.SetCookies(u, []*http.Cookie{
+		{Name: "tracker", Value: "1", Domain: "co.uk"},
+		{Name: "other", Value: "1", Domain: "evil.com"},
+		{Name: "session", Value: "old"},
+		{Name: "theme", Value: "dark"},
+	})
+	// Cookie with default path and cookie with explicit path are the same cookie
+	jar.SetCookies(u, []*http.Cookie{
+		{Name: "session", Value: "new", Path: "/account"},
+		{Name: "theme", Path: "/account", MaxAge: -1},
+	})
+
+	var b bytes.Buffer
+	if err := jar.Save(&b); err != nil {
+		t.Errorf("unexpected error: %s", err)
+		return
+	}
+	loaded := httpc.NewCookieJar()
+	if err := loaded.Load(&b); err != nil {
+		t.Errorf("unexpected error: %s", err)
+		return
+	}
+	cookies := loaded.Cookies(u)
+	if len(cookies) != 1 || cookies[0].Name != "session" || cookies[0].Value != "new" {
+		t.Errorf("unexpected loaded cookies: %v", cookies)
+	}
+}
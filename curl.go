@@ -0,0 +1,150 @@
+package httpc
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	logOption "github.com/nbs-go/nlogger/v2/option"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"sort"
+	"strings"
+	"unicode/utf8"
+)
+
+// ToCurl returns curl command that sends the same request. Multipart form body is converted to --form-string
+// arguments for text fields and -F arguments for files. File content is not included in command, it is referenced by
+// its file name, so the file must exist when command is run. Accept-Encoding header is converted to --compressed.
+// Sensitive data is not redacted, use LogAsCurl client option to log redacted command
+func ToCurl(req *http.Request, body []byte) string {
+	args := []string{"curl"}
+	switch req.Method {
+	case "", MethodGet:
+	case MethodHead:
+		args = append(args, "--head")
+	default:
+		args = append(args, "-X", req.Method)
+	}
+	args = append(args, shellQuote(req.URL.String()))
+	// Convert multipart body to form arguments
+	var formArgs []string
+	mt, params, _ := mime.ParseMediaType(req.Header.Get(HeaderContentType))
+	if mt == "multipart/form-data" && len(body) > 0 {
+		var err error
+		formArgs, err = curlFormArgs(body, params["boundary"])
+		if err != nil {
+			formArgs = nil
+		}
+	}
+	// Set header
+	keys := make([]string, 0, len(req.Header))
+	for k := range req.Header {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		switch {
+		case strings.EqualFold(k, "Accept-Encoding"):
+			args = append(args, "--compressed")
+			continue
+		case formArgs != nil && strings.EqualFold(k, HeaderContentType):
+			// Content-Type with boundary is set by curl
+			continue
+		}
+		for _, v := range req.Header[k] {
+			args = append(args, "-H", shellQuote(k+": "+v))
+		}
+	}
+	if req.Host != "" && req.Host != req.URL.Host {
+		args = append(args, "-H", shellQuote("Host: "+req.Host))
+	}
+	// Set body
+	switch {
+	case formArgs != nil:
+		args = append(args, formArgs...)
+	case len(body) > 0 && body[0] == '@':
+		// Body that starts with @ is read from file by --data-binary
+		args = append(args, "--data-raw", shellQuote(string(body)))
+	case len(body) > 0:
+		args = append(args, "--data-binary", shellQuote(string(body)))
+	}
+	return strings.Join(args, " ")
+}
+
+// curlFormArgs returns form arguments of multipart form body. Text field is set with --form-string, so value that
+// starts with @ or < is not read from file
+func curlFormArgs(body []byte, boundary string) ([]string, error) {
+	if boundary == "" {
+		return nil, fmt.Errorf("httpc: Multipart boundary is not set")
+	}
+	var args []string
+	r := multipart.NewReader(bytes.NewReader(body), boundary)
+	for {
+		p, err := r.NextPart()
+		if err == io.EOF {
+			return args, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		if fn := p.FileName(); fn != "" {
+			v := "@" + fn
+			if ct := p.Header.Get(HeaderContentType); ct != "" {
+				v += ";type=" + ct
+			}
+			args = append(args, "-F", shellQuote(p.FormName()+"="+v))
+			continue
+		}
+		b, rErr := io.ReadAll(p)
+		if rErr != nil {
+			return nil, rErr
+		}
+		args = append(args, "--form-string", shellQuote(p.FormName()+"="+string(b)))
+	}
+}
+
+// shellQuote quotes s as a single POSIX shell word. String that contains non-printable characters is quoted with
+// ANSI-C quoting
+func shellQuote(s string) string {
+	if !utf8.ValidString(s) || strings.IndexFunc(s, isNonPrintable) >= 0 {
+		var b strings.Builder
+		b.WriteString("$'")
+		for i := 0; i < len(s); i++ {
+			c := s[i]
+			switch {
+			case c == '\\' || c == '\'':
+				b.WriteByte('\\')
+				b.WriteByte(c)
+			case c == '\n':
+				b.WriteString(`\n`)
+			case c == '\r':
+				b.WriteString(`\r`)
+			case c == '\t':
+				b.WriteString(`\t`)
+			case c < 0x20 || c >= 0x7f:
+				_, _ = fmt.Fprintf(&b, `\x%02x`, c)
+			default:
+				b.WriteByte(c)
+			}
+		}
+		b.WriteByte('\'')
+		return b.String()
+	}
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
+
+func isNonPrintable(r rune) bool {
+	return r < 0x20 && r != '\n' && r != '\t' || r == 0x7f
+}
+
+// logCurl logs request as redacted curl command
+func (c *Client) logCurl(ctx context.Context, req *http.Request, reqBody []byte, reqId string) {
+	dr := c.cloneWithCookies(ctx, req, false)
+	c.redactor.redactHeader(dr.Header)
+	dr.URL = c.redactor.redactUrl(dr.URL)
+	body := c.redactor.redactBody(dr.Header.Get(HeaderContentType), reqBody)
+	c.log.Debug("HTTP Request as cURL (RequestId=%s)\n%s",
+		logOption.Format(reqId, c.redactor.redactText([]byte(ToCurl(dr, body)))), logOption.Context(ctx))
+}
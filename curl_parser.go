@@ -0,0 +1,531 @@
+package httpc
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/url"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// CurlRequest is a request parsed from curl command
+type CurlRequest struct {
+	Method string
+	Url    string
+	Header http.Header
+	// Body is the request body of -d and --data-urlencode options
+	Body []byte
+	// Form is the multipart form fields of -F option
+	Form []CurlFormField
+	// Username and Password are set by -u option
+	Username string
+	Password string
+	// Compressed is set by --compressed option. Response is decompressed by http.Transport by default
+	Compressed bool
+}
+
+// CurlFormField is a multipart form field
+type CurlFormField struct {
+	Name  string
+	Value string
+	// File is the file uploaded as file part, set by name=@file
+	File string
+	// ValueFile is the file which content is used as field value, set by name=<file
+	ValueFile   string
+	ContentType string
+}
+
+// curlNoArgOptions are curl options without argument that do not change request
+var curlNoArgOptions = map[string]bool{
+	"-s": true, "--silent": true, "-S": true, "--show-error": true, "-L": true, "--location": true,
+	"-v": true, "--verbose": true, "-i": true, "--include": true, "-k": true, "--insecure": true,
+	"-f": true, "--fail": true, "-#": true, "--progress-bar": true,
+}
+
+// ParseCurl parse curl command into CurlRequest. Supported options are -X, -H, -d, --data-raw, --data-binary,
+// --data-urlencode, -F, --form-string, -u, -G, -I, -A, -b, -e and --compressed. Options that only affect curl output are ignored
+func ParseCurl(cmd string) (*CurlRequest, error) {
+	args, err := splitShellWords(cmd)
+	if err != nil {
+		return nil, err
+	}
+	if len(args) == 0 || args[0] != "curl" {
+		return nil, fmt.Errorf("httpc: Command is not a curl command")
+	}
+	r := CurlRequest{Header: make(http.Header)}
+	var data []string
+	get := false
+	for i := 1; i < len(args); i++ {
+		opt := args[i]
+		// Split combined short options, e.g. -sXPOST into -s, -X and POST
+		if !strings.HasPrefix(opt, "--") && strings.HasPrefix(opt, "-") && len(opt) > 2 {
+			split := splitCurlShortOptions(opt)
+			args = append(args[:i], append(split, args[i+1:]...)...)
+			opt = args[i]
+		}
+		// Read option value
+		value := func() (string, error) {
+			if i+1 >= len(args) {
+				return "", fmt.Errorf("httpc: Missing curl option value. Option = %s", opt)
+			}
+			i++
+			return args[i], nil
+		}
+		if !strings.HasPrefix(opt, "-") || opt == "-" {
+			r.Url = opt
+			continue
+		}
+		if curlNoArgOptions[opt] {
+			continue
+		}
+		var v string
+		switch opt {
+		case "--compressed":
+			r.Compressed = true
+			continue
+		case "-G", "--get":
+			get = true
+			continue
+		case "-I", "--head":
+			r.Method = MethodHead
+			continue
+		}
+		if v, err = value(); err != nil {
+			return nil, err
+		}
+		switch opt {
+		case "-X", "--request":
+			r.Method = v
+		case "--url":
+			r.Url = v
+		case "-H", "--header":
+			k, hv, ok := cutString(v, ":")
+			if !ok {
+				return nil, fmt.Errorf("httpc: Invalid curl header. Header = %s", v)
+			}
+			r.Header.Add(strings.TrimSpace(k), strings.TrimSpace(hv))
+		case "-A", "--user-agent":
+			r.Header.Set("User-Agent", v)
+		case "-e", "--referer":
+			r.Header.Set("Referer", v)
+		case "-b", "--cookie":
+			r.Header.Add("Cookie", v)
+		case "-u", "--user":
+			// curl prompts for password if it is not set
+			var ok bool
+			if r.Username, r.Password, ok = cutString(v, ":"); !ok {
+				return nil, fmt.Errorf("httpc: Curl password prompt is not supported, set password after colon. User = %s", v)
+			}
+		case "-d", "--data", "--data-ascii", "--data-binary":
+			if strings.HasPrefix(v, "@") {
+				return nil, fmt.Errorf("httpc: Reading curl data from file is not supported. Data = %s", v)
+			}
+			data = append(data, v)
+		case "--data-raw":
+			data = append(data, v)
+		case "--data-urlencode":
+			d, dErr := curlUrlEncode(v)
+			if dErr != nil {
+				return nil, dErr
+			}
+			data = append(data, d)
+		case "-F", "--form":
+			f, fErr := parseCurlFormField(v)
+			if fErr != nil {
+				return nil, fErr
+			}
+			r.Form = append(r.Form, f)
+		case "--form-string":
+			name, fv, ok := cutString(v, "=")
+			if !ok || name == "" {
+				return nil, fmt.Errorf("httpc: Invalid curl form field. Form = %s", v)
+			}
+			r.Form = append(r.Form, CurlFormField{Name: name, Value: fv})
+		default:
+			return nil, fmt.Errorf("httpc: Unsupported curl option. Option = %s", opt)
+		}
+	}
+	if r.Url == "" {
+		return nil, fmt.Errorf("httpc: Url is not set in curl command")
+	}
+	if len(data) > 0 && len(r.Form) > 0 {
+		return nil, fmt.Errorf("httpc: Data and form options could not be used together in curl command")
+	}
+	// Set data as query if -G is set, otherwise as body
+	d := strings.Join(data, "&")
+	switch {
+	case get && len(data) > 0:
+		sep := "?"
+		if strings.Contains(r.Url, "?") {
+			sep = "&"
+		}
+		r.Url += sep + d
+		if r.Method == "" {
+			r.Method = MethodGet
+		}
+	case len(data) > 0:
+		r.Body = []byte(d)
+		if r.Header.Get(HeaderContentType) == "" {
+			r.Header.Set(HeaderContentType, MimeTypeUrlEncodedForm)
+		}
+	}
+	if r.Method == "" {
+		r.Method = MethodGet
+		if len(r.Body) > 0 || len(r.Form) > 0 {
+			r.Method = MethodPost
+		}
+	}
+	return &r, nil
+}
+
+// Request creates Request to execute with Client. If url has baseUrl prefix, endpoint path is relative to baseUrl,
+// otherwise endpoint path is absolute and Client must be created with AllowAbsoluteUrl option
+func (r *CurlRequest) Request(baseUrl string) (*Request, error) {
+	var args []SetRequestOptionFn
+	for _, k := range sortedKeys(r.Header) {
+		for _, v := range r.Header[k] {
+			args = append(args, AddHeader(k, v))
+		}
+	}
+	if r.Username != "" || r.Password != "" {
+		args = append(args, BasicAuth(r.Username, r.Password))
+	}
+	switch {
+	case len(r.Form) > 0:
+		body, ct, err := r.multipartBody()
+		if err != nil {
+			return nil, err
+		}
+		args = append(args, SetHeader(HeaderContentType, ct), SetBody(body))
+	case len(r.Body) > 0:
+		args = append(args, SetBody(r.Body))
+	}
+	return NewRequest(r.Method, r.endpointPath(baseUrl), args...), nil
+}
+
+// GoCode returns Go code that creates equivalent RESTRequest, with endpoint path relative to baseUrl.
+// clientVar is the variable name of Client. Multipart form is not supported, use Request instead
+func (r *CurlRequest) GoCode(clientVar string, baseUrl string) (string, error) {
+	if len(r.Form) > 0 {
+		return "", fmt.Errorf("httpc: Multipart form is not supported in Go code. Form = %s", r.Form[0].Name)
+	}
+	// Compose body option first, Content-Type header is skipped if it is set by body option
+	body, ctSet := r.goBodyOption()
+	var b strings.Builder
+	_, _ = fmt.Fprintf(&b, "httpc.NewRESTRequest(%s, %s, %s)", clientVar, goMethod(r.Method),
+		strconv.Quote(r.endpointPath(baseUrl)))
+	for _, k := range sortedKeys(r.Header) {
+		if ctSet && strings.EqualFold(k, HeaderContentType) {
+			continue
+		}
+		for _, v := range r.Header[k] {
+			_, _ = fmt.Fprintf(&b, ".\n\tAddHeader(%s, %s)", strconv.Quote(k), strconv.Quote(v))
+		}
+	}
+	if r.Username != "" || r.Password != "" {
+		_, _ = fmt.Fprintf(&b, ".\n\tBasicAuth(%s, %s)", strconv.Quote(r.Username), strconv.Quote(r.Password))
+	}
+	b.WriteString(body)
+	return b.String(), nil
+}
+
+// goBodyOption returns Go code of body option and whether the option sets Content-Type header
+func (r *CurlRequest) goBodyOption() (string, bool) {
+	ct := r.Header.Get(HeaderContentType)
+	var b strings.Builder
+	switch {
+	case len(r.Body) == 0:
+		return "", false
+	case isJsonContentType(ct) && json.Valid(r.Body):
+		_, _ = fmt.Fprintf(&b, ".\n\tBody(json.RawMessage(%s))", goStringLiteral(string(r.Body)))
+		// Body sets Content-Type without parameters, restore Content-Type with parameters, e.g. charset
+		if ct != MimeTypeJson {
+			_, _ = fmt.Fprintf(&b, ".\n\tSetHeader(httpc.HeaderContentType, %s)", strconv.Quote(ct))
+		}
+		return b.String(), true
+	case ct == MimeTypeUrlEncodedForm:
+		form, err := url.ParseQuery(string(r.Body))
+		if err != nil {
+			break
+		}
+		b.WriteString(".\n\tAddOption(httpc.SetUrlEncodedFormBody(url.Values{")
+		for _, k := range sortedKeys(form) {
+			values := make([]string, len(form[k]))
+			for i, v := range form[k] {
+				values[i] = strconv.Quote(v)
+			}
+			_, _ = fmt.Fprintf(&b, "\n\t\t%s: {%s},", strconv.Quote(k), strings.Join(values, ", "))
+		}
+		b.WriteString("\n\t}))")
+		return b.String(), true
+	}
+	_, _ = fmt.Fprintf(&b, ".\n\tAddOption(httpc.SetBody([]byte(%s)))", goStringLiteral(string(r.Body)))
+	return b.String(), false
+}
+
+// endpointPath returns url relative to baseUrl, or url if it does not have baseUrl prefix
+func (r *CurlRequest) endpointPath(baseUrl string) string {
+	base := strings.TrimSuffix(baseUrl, "/")
+	if base != "" && strings.HasPrefix(r.Url, base) {
+		p := r.Url[len(base):]
+		if p == "" || p[0] == '/' || p[0] == '?' {
+			return "/" + strings.TrimPrefix(p, "/")
+		}
+	}
+	return r.Url
+}
+
+// multipartBody compose multipart form body. File content is read from file system
+func (r *CurlRequest) multipartBody() ([]byte, string, error) {
+	var b bytes.Buffer
+	w := multipart.NewWriter(&b)
+	for _, f := range r.Form {
+		if f.File == "" && f.ValueFile == "" {
+			if err := w.WriteField(f.Name, f.Value); err != nil {
+				return nil, "", err
+			}
+			continue
+		}
+		// Set file name for file part, field value part only has name
+		h := make(map[string][]string)
+		path, ct := f.ValueFile, f.ContentType
+		disposition := fmt.Sprintf(`form-data; name="%s"`, quoteEscape(f.Name))
+		if f.File != "" {
+			path = f.File
+			disposition += fmt.Sprintf(`; filename="%s"`, quoteEscape(path[strings.LastIndex(path, "/")+1:]))
+			if ct == "" {
+				ct = "application/octet-stream"
+			}
+		}
+		h["Content-Disposition"] = []string{disposition}
+		if ct != "" {
+			h[HeaderContentType] = []string{ct}
+		}
+		pw, err := w.CreatePart(h)
+		if err != nil {
+			return nil, "", err
+		}
+		file, err := os.Open(path)
+		if err != nil {
+			return nil, "", fmt.Errorf("httpc: Failed to read form file. File = %s, Error = %w", path, err)
+		}
+		_, err = io.Copy(pw, file)
+		_ = file.Close()
+		if err != nil {
+			return nil, "", err
+		}
+	}
+	if err := w.Close(); err != nil {
+		return nil, "", err
+	}
+	return b.Bytes(), w.FormDataContentType(), nil
+}
+
+// parseCurlFormField parse -F value, e.g. "name=value", "name=@file;type=image/png" or "name=<file"
+func parseCurlFormField(v string) (CurlFormField, error) {
+	name, value, ok := cutString(v, "=")
+	if !ok {
+		return CurlFormField{}, fmt.Errorf("httpc: Invalid curl form field. Form = %s", v)
+	}
+	f := CurlFormField{Name: name}
+	if !strings.HasPrefix(value, "@") && !strings.HasPrefix(value, "<") {
+		f.Value = value
+		return f, nil
+	}
+	parts := strings.Split(value[1:], ";")
+	if value[0] == '@' {
+		f.File = parts[0]
+	} else {
+		f.ValueFile = parts[0]
+	}
+	for _, p := range parts[1:] {
+		if strings.HasPrefix(p, "type=") {
+			f.ContentType = strings.TrimPrefix(p, "type=")
+		}
+	}
+	return f, nil
+}
+
+// curlUrlEncode returns encoded --data-urlencode value
+func curlUrlEncode(v string) (string, error) {
+	if i := strings.IndexAny(v, "=@"); i >= 0 {
+		if v[i] == '@' {
+			return "", fmt.Errorf("httpc: Reading curl data from file is not supported. Data = %s", v)
+		}
+		if i == 0 {
+			return url.QueryEscape(v[1:]), nil
+		}
+		return v[:i] + "=" + url.QueryEscape(v[i+1:]), nil
+	}
+	return url.QueryEscape(v), nil
+}
+
+// splitCurlShortOptions splits combined short options, e.g. -sSL or -sXPOST. Options without argument come first,
+// the last option could take argument which value is the rest of opt
+func splitCurlShortOptions(opt string) []string {
+	var opts []string
+	for i := 1; i < len(opt); i++ {
+		o := "-" + opt[i:i+1]
+		opts = append(opts, o)
+		if curlNoArgOptions[o] || o == "-G" || o == "-I" {
+			continue
+		}
+		// The rest is the value of option that takes argument
+		if i+1 < len(opt) {
+			opts = append(opts, opt[i+1:])
+		}
+		break
+	}
+	return opts
+}
+
+// splitShellWords split command line into words, supporting single quote, double quote, ANSI-C quote, backslash
+// escape and line continuation
+func splitShellWords(s string) ([]string, error) {
+	var words []string
+	var w strings.Builder
+	inWord := false
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+			if inWord {
+				words = append(words, w.String())
+				w.Reset()
+				inWord = false
+			}
+		case c == '\\':
+			if i+1 < len(s) {
+				i++
+				if s[i] == '\n' {
+					// Line continuation
+					continue
+				}
+				inWord = true
+				w.WriteByte(s[i])
+			}
+		case c == '\'':
+			inWord = true
+			end := strings.IndexByte(s[i+1:], '\'')
+			if end < 0 {
+				return nil, fmt.Errorf("httpc: Unterminated single quote in command")
+			}
+			w.WriteString(s[i+1 : i+1+end])
+			i += end + 1
+		case c == '$' && i+1 < len(s) && s[i+1] == '\'':
+			inWord = true
+			n, err := readAnsiCQuote(s[i+2:], &w)
+			if err != nil {
+				return nil, err
+			}
+			i += n + 2
+		case c == '"':
+			inWord = true
+			i++
+			for ; i < len(s) && s[i] != '"'; i++ {
+				if s[i] == '\\' && i+1 < len(s) && strings.IndexByte("\"\\$`\n", s[i+1]) >= 0 {
+					i++
+					if s[i] == '\n' {
+						continue
+					}
+				}
+				w.WriteByte(s[i])
+			}
+			if i >= len(s) {
+				return nil, fmt.Errorf("httpc: Unterminated double quote in command")
+			}
+		default:
+			inWord = true
+			w.WriteByte(c)
+		}
+	}
+	if inWord {
+		words = append(words, w.String())
+	}
+	return words, nil
+}
+
+// readAnsiCQuote reads $'...' quoted string content until closing quote and returns number of bytes read,
+// including closing quote
+func readAnsiCQuote(s string, w *strings.Builder) (int, error) {
+	for i := 0; i < len(s); i++ {
+		switch s[i] {
+		case '\'':
+			return i + 1, nil
+		case '\\':
+			if i+1 >= len(s) {
+				break
+			}
+			i++
+			switch s[i] {
+			case 'n':
+				w.WriteByte('\n')
+			case 'r':
+				w.WriteByte('\r')
+			case 't':
+				w.WriteByte('\t')
+			case 'x':
+				if i+2 < len(s) {
+					if v, err := strconv.ParseUint(s[i+1:i+3], 16, 8); err == nil {
+						w.WriteByte(byte(v))
+						i += 2
+						continue
+					}
+				}
+				w.WriteString(`\x`)
+			default:
+				w.WriteByte(s[i])
+			}
+		default:
+			w.WriteByte(s[i])
+		}
+	}
+	return 0, fmt.Errorf("httpc: Unterminated ANSI-C quote in command")
+}
+
+// cutString slices s around the first instance of sep
+func cutString(s string, sep string) (string, string, bool) {
+	if i := strings.Index(s, sep); i >= 0 {
+		return s[:i], s[i+len(sep):], true
+	}
+	return s, "", false
+}
+
+func goMethod(method string) string {
+	switch method {
+	case MethodGet:
+		return "httpc.MethodGet"
+	case MethodPost:
+		return "httpc.MethodPost"
+	case MethodPut:
+		return "httpc.MethodPut"
+	case MethodPatch:
+		return "httpc.MethodPatch"
+	case MethodDelete:
+		return "httpc.MethodDelete"
+	}
+	return strconv.Quote(method)
+}
+
+// goStringLiteral returns raw string literal if possible, otherwise quoted string literal
+func goStringLiteral(s string) string {
+	if !strings.Contains(s, "`") && strconv.CanBackquote(s) {
+		return "`" + s + "`"
+	}
+	return strconv.Quote(s)
+}
+
+func sortedKeys(m map[string][]string) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,178 @@
+package httpc_test
+
+import (
+	"context"
+	"github.com/nbs-go/httpc"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestParseCurl(t *testing.T) {
+	r, err := httpc.ParseCurl(`curl -sSL -XPUT 'https://api.example.com/users/1?x=1' \
+  -H 'Content-Type: application/json' -H "X-Note: it's" -u john:s3cret --compressed \
+  --data-raw $'{"name":"john\'s"}'`)
+	if err != nil {
+		t.Errorf("unexpected error: %s", err)
+		return
+	}
+	if r.Method != "PUT" || r.Url != "https://api.example.com/users/1?x=1" || !r.Compressed ||
+		r.Header.Get("X-Note") != "it's" || r.Username != "john" || r.Password != "s3cret" ||
+		string(r.Body) != `{"name":"john's"}` {
+		t.Errorf("unexpected parsed request: %+v, Body = %s", r, r.Body)
+	}
+
+	r, err = httpc.ParseCurl(`curl https://api.example.com/login -d user=john --data-urlencode 'note=a b&c'`)
+	if err != nil {
+		t.Errorf("unexpected error: %s", err)
+		return
+	}
+	if r.Method != "POST" || string(r.Body) != "user=john&note=a+b%26c" ||
+		r.Header.Get(httpc.HeaderContentType) != httpc.MimeTypeUrlEncodedForm {
+		t.Errorf("unexpected parsed request: %+v, Body = %s", r, r.Body)
+	}
+
+	r, err = httpc.ParseCurl(`curl -G https://api.example.com/search -d q=go`)
+	if err != nil || r.Method != "GET" || r.Url != "https://api.example.com/search?q=go" || len(r.Body) > 0 {
+		t.Errorf("unexpected parsed request: %+v, Error = %v", r, err)
+	}
+
+	r, err = httpc.ParseCurl(`curl -F name=john -F 'avatar=@/tmp/a.png;type=image/png' -F 'bio=</tmp/bio.txt' ` +
+		`https://api.example.com/upload`)
+	if err != nil || r.Method != "POST" || len(r.Form) != 3 || r.Form[1].File != "/tmp/a.png" ||
+		r.Form[1].ContentType != "image/png" || r.Form[2].File != "" || r.Form[2].ValueFile != "/tmp/bio.txt" {
+		t.Errorf("unexpected parsed request: %+v, Error = %v", r, err)
+	}
+
+	// Assert combined short options
+	r, err = httpc.ParseCurl(`curl -sXPOST https://api.example.com/users -sSH 'X-Id: 1' -sLd a=1 -sGI`)
+	if err != nil || r.Method != "HEAD" || r.Header.Get("X-Id") != "1" || r.Url != "https://api.example.com/users?a=1" {
+		t.Errorf("unexpected parsed request: %+v, Error = %v", r, err)
+	}
+
+	// Assert user with empty password
+	r, err = httpc.ParseCurl(`curl -u 'john:' https://api.example.com`)
+	if err != nil || r.Username != "john" || r.Password != "" {
+		t.Errorf("unexpected parsed request: %+v, Error = %v", r, err)
+	}
+}
+
+func TestParseCurlError(t *testing.T) {
+	for _, cmd := range []string{
+		`wget https://api.example.com`,
+		`curl -H 'X-Id: 1'`,
+		`curl https://api.example.com -o out.json`,
+		`curl https://api.example.com -d @body.json`,
+		`curl https://api.example.com -H 'X-Id: 1`,
+		`curl https://api.example.com -H`,
+		`curl https://api.example.com -u john`,
+		`curl https://api.example.com -so out.json`,
+	} {
+		if _, err := httpc.ParseCurl(cmd); err == nil {
+			t.Errorf("expected error is not returned. Command = %s", cmd)
+		}
+	}
+}
+
+func TestParseCurlToCurl(t *testing.T) {
+	req, _ := http.NewRequest("PATCH", "https://api.example.com/users/1", nil)
+	req.Header.Set(httpc.HeaderContentType, httpc.MimeTypeJson)
+	req.Header.Set("X-Note", "it's")
+	body := "{\"name\":\"a\x00b\"}"
+	r, err := httpc.ParseCurl(httpc.ToCurl(req, []byte(body)))
+	if err != nil {
+		t.Errorf("unexpected error: %s", err)
+		return
+	}
+	if r.Method != "PATCH" || r.Url != req.URL.String() || r.Header.Get("X-Note") != "it's" || string(r.Body) != body {
+		t.Errorf("unexpected parsed request: %+v, Body = %q", r, r.Body)
+	}
+}
+
+func TestCurlRequest(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "note.txt")
+	_ = os.WriteFile(file, []byte("hello"), 0600)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		u, p, _ := r.BasicAuth()
+		_ = r.ParseMultipartForm(1024)
+		f, _, err := r.FormFile("note")
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		// Field value from file is not a file part
+		if _, _, err = r.FormFile("bio"); err == nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		content, _ := io.ReadAll(f)
+		_, _ = io.WriteString(w, strings.Join([]string{r.Method, r.URL.String(), u, p, r.FormValue("name"),
+			string(content), r.FormValue("bio")}, " "))
+	}))
+	defer srv.Close()
+	rc := httpc.MustNewClient(srv.URL)
+
+	r, err := httpc.ParseCurl("curl -u john:s3cret " + srv.URL + "/upload?x=1 -F name=john -F note=@" + file +
+		" -F bio=<" + file)
+	if err != nil {
+		t.Errorf("unexpected error: %s", err)
+		return
+	}
+	req, err := r.Request(srv.URL)
+	if err != nil {
+		t.Errorf("unexpected error: %s", err)
+		return
+	}
+	result, err := rc.Execute(context.Background(), req)
+	if err != nil {
+		t.Errorf("unexpected error: %s", err)
+		return
+	}
+	if s := result.Text(); s != "POST /upload?x=1 john s3cret john hello hello" {
+		t.Errorf("unexpected response: %s", s)
+	}
+}
+
+func TestCurlRequestGoCode(t *testing.T) {
+	r, _ := httpc.ParseCurl(`curl -X POST https://api.example.com/v1/users -H 'Content-Type: application/json' ` +
+		`-H 'X-Tenant: a' -d '{"name":"john"}'`)
+	code, err := r.GoCode("client", "https://api.example.com/v1/")
+	expected := "httpc.NewRESTRequest(client, httpc.MethodPost, \"/users\").\n" +
+		"\tAddHeader(\"X-Tenant\", \"a\").\n" +
+		"\tBody(json.RawMessage(`{\"name\":\"john\"}`))"
+	if err != nil || code != expected {
+		t.Errorf("unexpected go code.\nExpected = %s\nActual   = %s\nError = %v", expected, code, err)
+	}
+
+	// Assert Content-Type parameters are kept
+	r, _ = httpc.ParseCurl(`curl -X PUT https://api.example.com/v1/users/1 ` +
+		`-H 'Content-Type: application/json; charset=utf-8' -d '{"name":"john"}'`)
+	code, err = r.GoCode("client", "https://api.example.com/v1")
+	expected = "httpc.NewRESTRequest(client, httpc.MethodPut, \"/users/1\").\n" +
+		"\tBody(json.RawMessage(`{\"name\":\"john\"}`)).\n" +
+		"\tSetHeader(httpc.HeaderContentType, \"application/json; charset=utf-8\")"
+	if err != nil || code != expected {
+		t.Errorf("unexpected go code.\nExpected = %s\nActual   = %s\nError = %v", expected, code, err)
+	}
+
+	// Assert multipart form is not supported
+	r, _ = httpc.ParseCurl(`curl https://api.example.com/upload -F name=john`)
+	if _, err = r.GoCode("client", "https://api.example.com"); err == nil {
+		t.Errorf("expected error is not returned")
+	}
+
+	r, _ = httpc.ParseCurl(`curl https://api.example.com/login -u john:s3cret -d user=john -d user=jane`)
+	code, err = r.GoCode("rc", "https://other.example.com")
+	expected = "httpc.NewRESTRequest(rc, httpc.MethodPost, \"https://api.example.com/login\").\n" +
+		"\tBasicAuth(\"john\", \"s3cret\").\n" +
+		"\tAddOption(httpc.SetUrlEncodedFormBody(url.Values{\n" +
+		"\t\t\"user\": {\"john\", \"jane\"},\n" +
+		"\t}))"
+	if err != nil || code != expected {
+		t.Errorf("unexpected go code.\nExpected = %s\nActual   = %s\nError = %v", expected, code, err)
+	}
+}
@@ -0,0 +1,91 @@
+package httpc_test
+
+import (
+	"bytes"
+	"context"
+	"github.com/nbs-go/httpc"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestToCurl(t *testing.T) {
+	req, _ := http.NewRequest("POST", "https://api.example.com/users?q=a+b", nil)
+	req.Header.Set(httpc.HeaderContentType, httpc.MimeTypeJson)
+	req.Header.Set("Accept-Encoding", "gzip")
+	req.Header.Set("X-Note", "it's")
+	cmd := httpc.ToCurl(req, []byte(`{"name":"john"}`))
+	expected := `curl -X POST 'https://api.example.com/users?q=a+b' --compressed -H 'Content-Type: application/json' ` +
+		`-H 'X-Note: it'\''s' --data-binary '{"name":"john"}'`
+	if cmd != expected {
+		t.Errorf("unexpected curl command.\nExpected = %s\nActual   = %s", expected, cmd)
+	}
+
+	req, _ = http.NewRequest("HEAD", "https://api.example.com/", nil)
+	if cmd = httpc.ToCurl(req, nil); cmd != `curl --head 'https://api.example.com/'` {
+		t.Errorf("unexpected curl command: %s", cmd)
+	}
+
+	req, _ = http.NewRequest("PUT", "https://api.example.com/raw", nil)
+	if cmd = httpc.ToCurl(req, []byte("a\x00b")); cmd != `curl -X PUT 'https://api.example.com/raw' --data-binary $'a\x00b'` {
+		t.Errorf("unexpected curl command: %s", cmd)
+	}
+
+	// Assert body that starts with @ is not read from file
+	if cmd = httpc.ToCurl(req, []byte("@data.json")); cmd != `curl -X PUT 'https://api.example.com/raw' --data-raw '@data.json'` {
+		t.Errorf("unexpected curl command: %s", cmd)
+	}
+}
+
+func TestToCurlMultipart(t *testing.T) {
+	var body bytes.Buffer
+	w := multipart.NewWriter(&body)
+	_ = w.WriteField("name", "john")
+	_ = w.WriteField("note", "<note.txt;type=text/plain")
+	fw, _ := w.CreateFormFile("avatar", "avatar.png")
+	_, _ = fw.Write([]byte("\x89PNG"))
+	_ = w.Close()
+	req, _ := http.NewRequest("POST", "https://api.example.com/upload", nil)
+	req.Header.Set(httpc.HeaderContentType, w.FormDataContentType())
+
+	cmd := httpc.ToCurl(req, body.Bytes())
+	expected := `curl -X POST 'https://api.example.com/upload' --form-string 'name=john' ` +
+		`--form-string 'note=<note.txt;type=text/plain' -F 'avatar=@avatar.png;type=application/octet-stream'`
+	if cmd != expected {
+		t.Errorf("unexpected curl command.\nExpected = %s\nActual   = %s", expected, cmd)
+	}
+
+	// Assert text field is parsed as value
+	r, err := httpc.ParseCurl(cmd)
+	if err != nil || len(r.Form) != 3 || r.Form[1].Value != "<note.txt;type=text/plain" || r.Form[1].ValueFile != "" ||
+		r.Form[2].File != "avatar.png" {
+		t.Errorf("unexpected parsed request: %+v, Error = %v", r, err)
+	}
+}
+
+func TestLogAsCurl(t *testing.T) {
+	l, restore := newCaptureLogger()
+	defer restore()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer srv.Close()
+	rc := httpc.MustNewClient(srv.URL, httpc.LogAsCurl(), httpc.ClientBearerToken("s3cret-bearer"))
+
+	_, _, err := rc.DoRequest(context.Background(), "POST", "/login",
+		httpc.SetJsonBody(map[string]string{"password": "s3cret-password"}))
+	if err != nil {
+		t.Errorf("unexpected error: %s", err)
+		return
+	}
+	entries := l.Entries("HTTP Request as cURL")
+	if len(entries) != 1 {
+		t.Errorf("unexpected log count: %d", len(entries))
+		return
+	}
+	msg := entries[0].msg
+	if strings.Contains(msg, "s3cret") || !strings.Contains(msg, "curl -X POST '"+srv.URL+"/login'") ||
+		!strings.Contains(msg, `-H 'Authorization: ***'`) || !strings.Contains(msg, `--data-binary '{"password":"***"}'`) {
+		t.Errorf("unexpected curl log: %s", msg)
+	}
+}
@@ -0,0 +1,216 @@
+package httpc
+
+import (
+	"context"
+	"crypto/md5"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"hash"
+	"net/http"
+	"strings"
+	"sync"
+)
+
+// Digest authentication qop values
+const (
+	DigestQopAuth    = "auth"
+	DigestQopAuthInt = "auth-int"
+)
+
+// NewDigestAuth creates AuthProvider that authenticate request using HTTP Digest authentication as defined in
+// RFC 7616. Credentials are sent after server responds with 401 challenge, then the challenge is reused for the
+// following requests until server responds with a new challenge
+func NewDigestAuth(username string, password string) AuthProvider {
+	return &digestAuth{
+		username: username,
+		password: password,
+	}
+}
+
+type digestAuth struct {
+	username  string
+	password  string
+	mu        sync.Mutex
+	challenge *digestChallenge
+	nc        uint32
+}
+
+type digestChallenge struct {
+	realm     string
+	nonce     string
+	opaque    string
+	algorithm string
+	qop       string
+	userhash  bool
+}
+
+func (a *digestAuth) Authenticate(_ context.Context, req *http.Request, body []byte) error {
+	a.mu.Lock()
+	ch := a.challenge
+	if ch == nil {
+		a.mu.Unlock()
+		return nil
+	}
+	a.nc++
+	nc := a.nc
+	a.mu.Unlock()
+	v, err := ch.authorization(req.Method, req.URL.RequestURI(), body, a.username, a.password, nc, randomNonce())
+	if err != nil {
+		return err
+	}
+	req.Header.Set(HeaderAuthorization, v)
+	return nil
+}
+
+func (a *digestAuth) Refresh(_ context.Context, resp *http.Response) (bool, error) {
+	ch := parseDigestChallenges(resp.Header.Values("WWW-Authenticate"))
+	if ch == nil {
+		return false, nil
+	}
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.challenge = ch
+	a.nc = 0
+	return true, nil
+}
+
+// authorization compose Authorization header value
+func (ch *digestChallenge) authorization(method string, uri string, body []byte, username string, password string,
+	nc uint32, cnonce string) (string, error) {
+	var newHash func() hash.Hash
+	switch strings.ToUpper(strings.TrimSuffix(strings.ToLower(ch.algorithm), "-sess")) {
+	case "", "MD5":
+		newHash = md5.New
+	case "SHA-256":
+		newHash = sha256.New
+	default:
+		return "", fmt.Errorf("httpc: Unsupported digest algorithm. Algorithm = %s", ch.algorithm)
+	}
+	h := func(s string) string {
+		d := newHash()
+		d.Write([]byte(s))
+		return hex.EncodeToString(d.Sum(nil))
+	}
+	// Compute response
+	ncs := fmt.Sprintf("%08x", nc)
+	ha1 := h(username + ":" + ch.realm + ":" + password)
+	if strings.HasSuffix(strings.ToLower(ch.algorithm), "-sess") {
+		ha1 = h(ha1 + ":" + ch.nonce + ":" + cnonce)
+	}
+	ha2 := h(method + ":" + uri)
+	if ch.qop == DigestQopAuthInt {
+		ha2 = h(method + ":" + uri + ":" + h(string(body)))
+	}
+	var response string
+	if ch.qop == "" {
+		response = h(ha1 + ":" + ch.nonce + ":" + ha2)
+	} else {
+		response = h(strings.Join([]string{ha1, ch.nonce, ncs, cnonce, ch.qop, ha2}, ":"))
+	}
+	// Compose header
+	user := username
+	if ch.userhash {
+		user = h(username + ":" + ch.realm)
+	}
+	var b strings.Builder
+	_, _ = fmt.Fprintf(&b, `Digest username="%s", realm="%s", nonce="%s", uri="%s", response="%s"`,
+		quoteEscape(user), quoteEscape(ch.realm), ch.nonce, uri, response)
+	if ch.algorithm != "" {
+		b.WriteString(", algorithm=" + ch.algorithm)
+	}
+	if ch.opaque != "" {
+		_, _ = fmt.Fprintf(&b, `, opaque="%s"`, ch.opaque)
+	}
+	if ch.qop != "" {
+		_, _ = fmt.Fprintf(&b, `, qop=%s, nc=%s, cnonce="%s"`, ch.qop, ncs, cnonce)
+	}
+	if ch.userhash {
+		b.WriteString(", userhash=true")
+	}
+	return b.String(), nil
+}
+
+// parseDigestChallenges parse WWW-Authenticate header values and returns the strongest supported Digest challenge
+func parseDigestChallenges(values []string) *digestChallenge {
+	var selected *digestChallenge
+	for _, v := range values {
+		v = strings.TrimSpace(v)
+		if len(v) < 7 || !strings.EqualFold(v[:7], "Digest ") {
+			continue
+		}
+		params := parseAuthParams(v[7:])
+		ch := digestChallenge{
+			realm:     params["realm"],
+			nonce:     params["nonce"],
+			opaque:    params["opaque"],
+			algorithm: params["algorithm"],
+			userhash:  strings.EqualFold(params["userhash"], "true"),
+		}
+		// Select qop, prefer auth over auth-int
+		if qop, ok := params["qop"]; ok {
+			for _, q := range strings.Split(qop, ",") {
+				q = strings.TrimSpace(q)
+				if q == DigestQopAuth || (q == DigestQopAuthInt && ch.qop == "") {
+					ch.qop = q
+				}
+			}
+			if ch.qop == "" {
+				continue
+			}
+		}
+		alg := strings.ToUpper(strings.TrimSuffix(strings.ToLower(ch.algorithm), "-sess"))
+		if ch.nonce == "" || (alg != "" && alg != "MD5" && alg != "SHA-256") {
+			continue
+		}
+		// Prefer SHA-256
+		if selected == nil || alg == "SHA-256" {
+			selected = &ch
+		}
+	}
+	return selected
+}
+
+// parseAuthParams parse comma-separated auth-param list with quoted-string values
+func parseAuthParams(s string) map[string]string {
+	params := make(map[string]string)
+	for len(s) > 0 {
+		s = strings.TrimLeft(s, " ,\t")
+		eq := strings.IndexByte(s, '=')
+		if eq < 0 {
+			break
+		}
+		key := strings.ToLower(strings.TrimSpace(s[:eq]))
+		s = strings.TrimLeft(s[eq+1:], " \t")
+		var val string
+		if strings.HasPrefix(s, `"`) {
+			// Read quoted string
+			var b strings.Builder
+			i := 1
+			for ; i < len(s) && s[i] != '"'; i++ {
+				if s[i] == '\\' && i+1 < len(s) {
+					i++
+				}
+				b.WriteByte(s[i])
+			}
+			val = b.String()
+			if i < len(s) {
+				i++
+			}
+			s = s[i:]
+		} else {
+			end := strings.IndexByte(s, ',')
+			if end < 0 {
+				end = len(s)
+			}
+			val = strings.TrimSpace(s[:end])
+			s = s[end:]
+		}
+		params[key] = val
+	}
+	return params
+}
+
+func quoteEscape(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(s)
+}
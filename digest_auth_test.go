@@ -0,0 +1,106 @@
+package httpc_test
+
+import (
+	"context"
+	"crypto/md5"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"github.com/nbs-go/httpc"
+	"hash"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+)
+
+// newDigestServer creates a server that verify Digest authentication with given algorithm and qop
+func newDigestServer(algorithm string, qop string, calls *int32) *httptest.Server {
+	newHash := md5.New
+	if algorithm == "SHA-256" {
+		newHash = sha256.New
+	}
+	h := func(fn func() hash.Hash, s string) string {
+		d := fn()
+		d.Write([]byte(s))
+		return hex.EncodeToString(d.Sum(nil))
+	}
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(calls, 1)
+		body, _ := io.ReadAll(r.Body)
+		auth := r.Header.Get(httpc.HeaderAuthorization)
+		if strings.HasPrefix(auth, "Digest ") {
+			p := map[string]string{}
+			for _, kv := range strings.Split(auth[7:], ", ") {
+				i := strings.Index(kv, "=")
+				p[kv[:i]] = strings.Trim(kv[i+1:], `"`)
+			}
+			ha1 := h(newHash, "user:test@nbs.dev:pass")
+			ha2 := h(newHash, r.Method+":"+p["uri"])
+			if p["qop"] == "auth-int" {
+				ha2 = h(newHash, r.Method+":"+p["uri"]+":"+h(newHash, string(body)))
+			}
+			expected := h(newHash, strings.Join([]string{ha1, "n0nce", p["nc"], p["cnonce"], p["qop"], ha2}, ":"))
+			if p["response"] == expected && p["uri"] == r.URL.RequestURI() && p["opaque"] == "0paque" {
+				_, _ = w.Write(body)
+				return
+			}
+		}
+		w.Header().Add("WWW-Authenticate", `Basic realm="test@nbs.dev"`)
+		w.Header().Add("WWW-Authenticate",
+			fmt.Sprintf(`Digest realm="test@nbs.dev", qop="%s", algorithm=%s, nonce="n0nce", opaque="0paque"`, qop, algorithm))
+		w.WriteHeader(http.StatusUnauthorized)
+	}))
+}
+
+func TestDigestAuth(t *testing.T) {
+	for _, tc := range []struct{ algorithm, qop string }{
+		{"MD5", "auth"},
+		{"SHA-256", "auth"},
+		{"SHA-256", "auth-int"},
+	} {
+		var calls int32
+		srv := newDigestServer(tc.algorithm, tc.qop, &calls)
+		dc := httpc.MustNewClient(srv.URL, httpc.DigestAuth("user", "pass"))
+
+		// Assert challenge is handled and body is replayed
+		for i := 0; i < 2; i++ {
+			resp, body, err := dc.DoRequest(context.Background(), "POST", "/dir/index.html",
+				httpc.AddQuery("page", "1"), httpc.SetBody([]byte("hello")))
+			if err != nil {
+				t.Errorf("unexpected error: %s", err)
+				break
+			}
+			if resp.StatusCode != http.StatusOK || string(body) != "hello" {
+				t.Errorf("unexpected response. Algorithm = %s, Qop = %s, Status = %d, Body = %s",
+					tc.algorithm, tc.qop, resp.StatusCode, body)
+			}
+		}
+		srv.Close()
+		// Assert challenge is reused on the second request
+		if n := atomic.LoadInt32(&calls); n != 3 {
+			t.Errorf("unexpected server call count: %d", n)
+		}
+	}
+}
+
+func TestDigestAuthInvalidCredentials(t *testing.T) {
+	var calls int32
+	srv := newDigestServer("MD5", "auth", &calls)
+	defer srv.Close()
+	dc := httpc.MustNewClient(srv.URL, httpc.DigestAuth("user", "invalid"))
+
+	resp, _, err := dc.DoRequest(context.Background(), "GET", "/")
+	if err != nil {
+		t.Errorf("unexpected error: %s", err)
+		return
+	}
+	if resp.StatusCode != http.StatusUnauthorized {
+		t.Errorf("unexpected response status code. StatusCode = %d", resp.StatusCode)
+	}
+	if n := atomic.LoadInt32(&calls); n != 2 {
+		t.Errorf("unexpected server call count: %d", n)
+	}
+}
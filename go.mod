@@ -5,4 +5,5 @@ go 1.17
 require (
 	github.com/google/uuid v1.3.0
 	github.com/nbs-go/nlogger/v2 v2.2.2
+	golang.org/x/net v0.17.0
 )
@@ -0,0 +1,341 @@
+package httpc
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"io"
+	"net/http"
+	"os"
+	"sort"
+	"sync"
+	"time"
+)
+
+// HARVersion is the version of HTTP Archive format
+const HARVersion = "1.2"
+
+type SetHAROptionFn func(r *HARRecorder)
+
+// HARRingBuffer keeps only the last n entries. By default, all entries are kept
+func HARRingBuffer(n int) SetHAROptionFn {
+	return func(r *HARRecorder) {
+		r.capacity = n
+	}
+}
+
+// NewHARRecorder creates recorder that captures requests and responses in HTTP Archive 1.2 format.
+// Attach it to Client using RecordHAR option. Sensitive data is redacted with Client log dump redaction
+func NewHARRecorder(args ...SetHAROptionFn) *HARRecorder {
+	r := HARRecorder{}
+	for _, fn := range args {
+		fn(&r)
+	}
+	return &r
+}
+
+// HARRecorder captures requests and responses of Client. It is safe for concurrent use
+type HARRecorder struct {
+	capacity int
+	mu       sync.Mutex
+	entries  []*HAREntry
+	// next is the index of the oldest entry when ring buffer is full
+	next int
+}
+
+// HAR is the root of HTTP Archive
+type HAR struct {
+	Log HARLog `json:"log"`
+}
+
+type HARLog struct {
+	Version string      `json:"version"`
+	Creator HARCreator  `json:"creator"`
+	Entries []*HAREntry `json:"entries"`
+}
+
+type HARCreator struct {
+	Name    string `json:"name"`
+	Version string `json:"version"`
+}
+
+type HAREntry struct {
+	StartedDateTime time.Time   `json:"startedDateTime"`
+	Time            float64     `json:"time"`
+	Request         HARRequest  `json:"request"`
+	Response        HARResponse `json:"response"`
+	Cache           struct{}    `json:"cache"`
+	Timings         HARTimings  `json:"timings"`
+}
+
+type HARRequest struct {
+	Method      string         `json:"method"`
+	Url         string         `json:"url"`
+	HttpVersion string         `json:"httpVersion"`
+	Cookies     []HARCookie    `json:"cookies"`
+	Headers     []HARNameValue `json:"headers"`
+	QueryString []HARNameValue `json:"queryString"`
+	PostData    *HARPostData   `json:"postData,omitempty"`
+	HeadersSize int            `json:"headersSize"`
+	BodySize    int            `json:"bodySize"`
+}
+
+type HARResponse struct {
+	Status      int            `json:"status"`
+	StatusText  string         `json:"statusText"`
+	HttpVersion string         `json:"httpVersion"`
+	Cookies     []HARCookie    `json:"cookies"`
+	Headers     []HARNameValue `json:"headers"`
+	Content     HARContent     `json:"content"`
+	RedirectUrl string         `json:"redirectURL"`
+	HeadersSize int            `json:"headersSize"`
+	BodySize    int            `json:"bodySize"`
+	// Error is the error message if request failed or response body could not be read
+	Error string `json:"_error,omitempty"`
+}
+
+type HARNameValue struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+type HARCookie struct {
+	Name     string `json:"name"`
+	Value    string `json:"value"`
+	Path     string `json:"path,omitempty"`
+	Domain   string `json:"domain,omitempty"`
+	HttpOnly bool   `json:"httpOnly,omitempty"`
+	Secure   bool   `json:"secure,omitempty"`
+}
+
+type HARPostData struct {
+	MimeType string `json:"mimeType"`
+	Text     string `json:"text"`
+}
+
+type HARContent struct {
+	Size     int    `json:"size"`
+	MimeType string `json:"mimeType"`
+	Text     string `json:"text,omitempty"`
+	Encoding string `json:"encoding,omitempty"`
+}
+
+// HARTimings is duration of request phases in milliseconds. Phase that does not apply is -1
+type HARTimings struct {
+	Blocked float64 `json:"blocked"`
+	DNS     float64 `json:"dns"`
+	Connect float64 `json:"connect"`
+	Send    float64 `json:"send"`
+	Wait    float64 `json:"wait"`
+	Receive float64 `json:"receive"`
+	SSL     float64 `json:"ssl"`
+}
+
+// Entries returns recorded entries from the oldest
+func (r *HARRecorder) Entries() []*HAREntry {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	entries := make([]*HAREntry, 0, len(r.entries))
+	entries = append(entries, r.entries[r.next:]...)
+	return append(entries, r.entries[:r.next]...)
+}
+
+// Reset removes recorded entries
+func (r *HARRecorder) Reset() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.entries = nil
+	r.next = 0
+}
+
+// HAR returns recorded entries as HTTP Archive
+func (r *HARRecorder) HAR() *HAR {
+	return &HAR{
+		Log: HARLog{
+			Version: HARVersion,
+			Creator: HARCreator{Name: "httpc", Version: "unknown"},
+			Entries: r.Entries(),
+		},
+	}
+}
+
+// WriteTo writes HTTP Archive as JSON to w
+func (r *HARRecorder) WriteTo(w io.Writer) (int64, error) {
+	b, err := json.MarshalIndent(r.HAR(), "", "  ")
+	if err != nil {
+		return 0, err
+	}
+	n, err := w.Write(b)
+	return int64(n), err
+}
+
+// WriteFile writes HTTP Archive to file, e.g. "trace.har", with 0600 permission
+func (r *HARRecorder) WriteFile(path string) error {
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0600)
+	if err != nil {
+		return err
+	}
+	_, err = r.WriteTo(f)
+	if cErr := f.Close(); err == nil {
+		err = cErr
+	}
+	return err
+}
+
+func (r *HARRecorder) record(e *HAREntry) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.capacity <= 0 || len(r.entries) < r.capacity {
+		r.entries = append(r.entries, e)
+		return
+	}
+	// Replace the oldest entry
+	r.entries[r.next] = e
+	r.next = (r.next + 1) % r.capacity
+}
+
+// recordHAR records an attempt if HAR recorder is set. resp is nil if request failed
+func (c *Client) recordHAR(req *http.Request, reqBody []byte, resp *http.Response, respBody []byte, tt *timingsTrace,
+	err error) {
+	if c.har == nil {
+		return
+	}
+	// Mark failed request is finished, so entry has total duration
+	if resp == nil {
+		tt.finish()
+	}
+	c.har.record(c.harEntry(req, reqBody, resp, respBody, tt, err))
+}
+
+// recordDiscardedHAR reads body of response that will be discarded, e.g. unauthorized response before request is
+// replayed, and records the attempt if HAR recorder is set
+func (c *Client) recordDiscardedHAR(req *http.Request, reqBody []byte, resp *http.Response, tt *timingsTrace) {
+	if c.har == nil {
+		return
+	}
+	respBody, err := io.ReadAll(resp.Body)
+	tt.finish()
+	c.recordHAR(req, reqBody, resp, respBody, tt, err)
+}
+
+// harEntry creates redacted HAR entry of request and response. If redirects are followed, request is the last
+// request that lead to the response
+func (c *Client) harEntry(req *http.Request, reqBody []byte, resp *http.Response, respBody []byte, tt *timingsTrace,
+	err error) *HAREntry {
+	if resp != nil && resp.Request != nil {
+		// Body is not sent if redirect changed method to GET
+		if resp.Request != req && resp.Request.ContentLength == 0 {
+			reqBody = nil
+		}
+		req = resp.Request
+	}
+	// Redact a copy of sent request. Cookies from jar are already set in its header
+	dr := c.cloneWithCookies(req.Context(), req, true)
+	c.redactor.redactHeader(dr.Header)
+	u := c.redactor.redactUrl(req.URL)
+	e := HAREntry{
+		StartedDateTime: tt.start,
+		Request: HARRequest{
+			Method:      req.Method,
+			Url:         u.String(),
+			HttpVersion: req.Proto,
+			Cookies:     harCookies(dr.Cookies()),
+			Headers:     harNameValues(dr.Header),
+			QueryString: harNameValues(u.Query()),
+			HeadersSize: -1,
+			BodySize:    len(reqBody),
+		},
+		Response: HARResponse{
+			Cookies:     []HARCookie{},
+			Headers:     []HARNameValue{},
+			Content:     HARContent{MimeType: "x-unknown"},
+			HeadersSize: -1,
+			BodySize:    -1,
+		},
+		Timings: HARTimings{Blocked: -1, DNS: -1, Connect: -1, SSL: -1},
+	}
+	if resp != nil {
+		respHeader := resp.Header.Clone()
+		c.redactor.redactHeader(respHeader)
+		e.Response = HARResponse{
+			Status:      resp.StatusCode,
+			StatusText:  http.StatusText(resp.StatusCode),
+			HttpVersion: resp.Proto,
+			Cookies:     harCookies((&http.Response{Header: respHeader}).Cookies()),
+			Headers:     harNameValues(respHeader),
+			Content:     c.harContent(resp.Header.Get(HeaderContentType), respBody),
+			RedirectUrl: resp.Header.Get("Location"),
+			HeadersSize: -1,
+			BodySize:    len(respBody),
+		}
+	}
+	if err != nil {
+		e.Response.Error = string(c.redactor.redactText([]byte(err.Error())))
+	}
+	if len(reqBody) > 0 {
+		ct := req.Header.Get(HeaderContentType)
+		e.Request.PostData = &HARPostData{
+			MimeType: ct,
+			Text:     string(c.redactor.redactText(c.redactor.redactBody(ct, reqBody))),
+		}
+	}
+	t := tt.timings()
+	e.Timings.DNS = harDuration(t.DNSLookup)
+	// HAR connect duration includes TLS handshake
+	e.Timings.Connect = harDuration(t.Connect + t.TLSHandshake)
+	e.Timings.SSL = harDuration(t.TLSHandshake)
+	e.Timings.Wait = milliseconds(t.TimeToFirstByte)
+	e.Timings.Receive = milliseconds(t.ContentTransfer)
+	e.Time = milliseconds(t.Total)
+	return &e
+}
+
+func (c *Client) harContent(contentType string, body []byte) HARContent {
+	hc := HARContent{Size: len(body), MimeType: contentType}
+	if len(body) == 0 {
+		return hc
+	}
+	if !isTextBody(contentType, body) {
+		hc.Text = base64.StdEncoding.EncodeToString(body)
+		hc.Encoding = "base64"
+		return hc
+	}
+	hc.Text = string(c.redactor.redactText(c.redactor.redactBody(contentType, body)))
+	return hc
+}
+
+// harDuration returns duration in milliseconds, or -1 if phase did not happen
+func harDuration(d time.Duration) float64 {
+	if d == 0 {
+		return -1
+	}
+	return milliseconds(d)
+}
+
+func harNameValues(values map[string][]string) []HARNameValue {
+	result := make([]HARNameValue, 0, len(values))
+	for k, vs := range values {
+		for _, v := range vs {
+			result = append(result, HARNameValue{Name: k, Value: v})
+		}
+	}
+	// Sort by name, so entries are stable across runs
+	sort.SliceStable(result, func(i, j int) bool {
+		return result[i].Name < result[j].Name
+	})
+	return result
+}
+
+func harCookies(cookies []*http.Cookie) []HARCookie {
+	result := make([]HARCookie, 0, len(cookies))
+	for _, ck := range cookies {
+		result = append(result, HARCookie{
+			Name:     ck.Name,
+			Value:    ck.Value,
+			Path:     ck.Path,
+			Domain:   ck.Domain,
+			HttpOnly: ck.HttpOnly,
+			Secure:   ck.Secure,
+		})
+	}
+	return result
+}
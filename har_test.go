@@ -0,0 +1,180 @@
+package httpc_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/nbs-go/httpc"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestHARRecorder(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/image":
+			w.Header().Set(httpc.HeaderContentType, "image/png")
+			_, _ = w.Write([]byte("\x89PNG\r\n\x1a\n"))
+		default:
+			http.SetCookie(w, &http.Cookie{Name: "session", Value: "s3cret-session"})
+			w.Header().Set(httpc.HeaderContentType, httpc.MimeTypeJson)
+			_, _ = w.Write([]byte(`{"access_token":"s3cret-token"}`))
+		}
+	}))
+	defer srv.Close()
+	har := httpc.NewHARRecorder()
+	rc := httpc.MustNewClient(srv.URL, httpc.RecordHAR(har), httpc.ClientBearerToken("s3cret-bearer"))
+
+	_, _, err := rc.DoRequest(context.Background(), "POST", "/login", httpc.AddQuery("api_key", "s3cret-key"),
+		httpc.SetJsonBody(map[string]string{"username": "john", "password": "s3cret-password"}))
+	if err != nil {
+		t.Errorf("unexpected error: %s", err)
+		return
+	}
+	_, _, err = rc.DoRequest(context.Background(), "GET", "/image")
+	if err != nil {
+		t.Errorf("unexpected error: %s", err)
+		return
+	}
+
+	path := filepath.Join(t.TempDir(), "trace.har")
+	if err = har.WriteFile(path); err != nil {
+		t.Errorf("unexpected error: %s", err)
+		return
+	}
+	b, _ := os.ReadFile(path)
+	if strings.Contains(string(b), "s3cret") {
+		t.Errorf("unexpected condition: sensitive data is not redacted.\n%s", b)
+	}
+	var h httpc.HAR
+	if err = json.Unmarshal(b, &h); err != nil {
+		t.Errorf("unexpected error: %s", err)
+		return
+	}
+	if h.Log.Version != "1.2" || len(h.Log.Entries) != 2 {
+		t.Errorf("unexpected HAR log. Version = %s, Entries = %d", h.Log.Version, len(h.Log.Entries))
+		return
+	}
+	login, image := h.Log.Entries[0], h.Log.Entries[1]
+	if login.Request.Method != "POST" || login.Request.PostData == nil ||
+		!strings.Contains(login.Request.PostData.Text, `"username":"john"`) || login.Response.Status != 200 ||
+		len(login.Response.Cookies) != 1 || login.Response.Cookies[0].Value != "***" ||
+		len(login.Request.QueryString) != 1 || login.Timings.Connect <= 0 || login.Timings.Wait <= 0 {
+		t.Errorf("unexpected login entry: %+v", login)
+	}
+	if image.Response.Content.Encoding != "base64" || image.Response.Content.Text != "iVBORw0KGgo=" ||
+		image.Timings.Connect != -1 {
+		t.Errorf("unexpected image entry: %+v", image)
+	}
+}
+
+func TestHARRingBuffer(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer srv.Close()
+	har := httpc.NewHARRecorder(httpc.HARRingBuffer(2))
+	rc := httpc.MustNewClient(srv.URL, httpc.RecordHAR(har))
+
+	for _, p := range []string{"/1", "/2", "/3"} {
+		_, _, err := rc.DoRequest(context.Background(), "GET", p)
+		if err != nil {
+			t.Errorf("unexpected error: %s", err)
+			return
+		}
+	}
+	entries := har.Entries()
+	if len(entries) != 2 || !strings.HasSuffix(entries[0].Request.Url, "/2") ||
+		!strings.HasSuffix(entries[1].Request.Url, "/3") {
+		t.Errorf("unexpected entries: %v", entries)
+	}
+}
+
+func TestHARRecorderError(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	srv.Close()
+	har := httpc.NewHARRecorder()
+	rc := httpc.MustNewClient(srv.URL, httpc.RecordHAR(har))
+
+	_, _, err := rc.DoRequest(context.Background(), "POST", "/users", httpc.SetJsonBody(map[string]string{"name": "john"}))
+	if err == nil {
+		t.Errorf("expected error is not returned")
+		return
+	}
+	entries := har.Entries()
+	if len(entries) != 1 {
+		t.Errorf("unexpected entry count: %d", len(entries))
+		return
+	}
+	e := entries[0]
+	if e.Request.Method != "POST" || e.Request.PostData == nil || e.Response.Status != 0 ||
+		!strings.Contains(e.Response.Error, "connection refused") || e.Time <= 0 {
+		t.Errorf("unexpected error entry: %+v", e)
+	}
+}
+
+func TestHARRecorderRedirect(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/old" {
+			http.Redirect(w, r, "/new", http.StatusSeeOther)
+			return
+		}
+		_, _ = w.Write([]byte("ok"))
+	}))
+	defer srv.Close()
+	har := httpc.NewHARRecorder()
+	rc := httpc.MustNewClient(srv.URL, httpc.RecordHAR(har))
+
+	_, _, err := rc.DoRequest(context.Background(), "POST", "/old", httpc.AddHeader("X-Id", "1"),
+		httpc.SetJsonBody(map[string]string{"name": "john"}))
+	if err != nil {
+		t.Errorf("unexpected error: %s", err)
+		return
+	}
+	entries := har.Entries()
+	if len(entries) != 1 {
+		t.Errorf("unexpected entry count: %d", len(entries))
+		return
+	}
+	e := entries[0]
+	if e.Request.Method != "GET" || e.Request.Url != srv.URL+"/new" || e.Request.PostData != nil ||
+		e.Response.Status != 200 || e.Response.Content.Text != "ok" {
+		t.Errorf("unexpected redirect entry. Request = %+v, Response = %+v", e.Request, e.Response)
+	}
+}
+
+func TestHARRecorderReplay(t *testing.T) {
+	var issued int32
+	src := httpc.TokenSourceFn(func(ctx context.Context) (*httpc.Token, error) {
+		n := atomic.AddInt32(&issued, 1)
+		return &httpc.Token{AccessToken: fmt.Sprintf("token-%d", n), Expiry: time.Now().Add(time.Hour)}, nil
+	})
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get(httpc.HeaderAuthorization) != "Bearer token-2" {
+			w.WriteHeader(http.StatusUnauthorized)
+			_, _ = w.Write([]byte("expired"))
+		}
+	}))
+	defer srv.Close()
+	har := httpc.NewHARRecorder()
+	rc := httpc.MustNewClient(srv.URL, httpc.RecordHAR(har), httpc.ClientTokenAuth(src))
+
+	result, err := rc.Execute(context.Background(), httpc.NewRequest("GET", "/"))
+	if err != nil {
+		t.Errorf("unexpected error: %s", err)
+		return
+	}
+	entries := har.Entries()
+	if len(entries) != 2 || entries[0].Response.Status != 401 || entries[0].Response.Content.Text != "expired" ||
+		entries[1].Response.Status != 200 {
+		t.Errorf("unexpected entries: %v", entries)
+	}
+	// Timings are collected for HAR only
+	if result.Timings != nil || httpc.ResponseTimings(result.Response) != nil {
+		t.Errorf("unexpected timings: %+v", result.Timings)
+	}
+}
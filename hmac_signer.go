@@ -0,0 +1,342 @@
+package httpc
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"hash"
+	"net/http"
+	"net/url"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// DefaultHMACTemplate is the default canonical string template of HMACSigner
+const DefaultHMACTemplate = "{method}\n{path}\n{query}\n{timestamp}\n{nonce}\n{body_hash}"
+
+// Timestamp formats for HMACTimestampHeader
+const (
+	TimestampUnix      = "unix"
+	TimestampUnixMilli = "unix_ms"
+)
+
+// SignatureEncoding defines how signature and body hash are encoded
+type SignatureEncoding int8
+
+const (
+	SignatureHex SignatureEncoding = iota
+	SignatureBase64
+)
+
+// HMACSigningData contains values to compose canonical string of a request
+type HMACSigningData struct {
+	Request   *http.Request
+	Body      []byte
+	Timestamp string
+	Nonce     string
+	// BodyHash is the encoded hash of Body
+	BodyHash string
+}
+
+// HMACCanonicalFn compose canonical string to be signed
+type HMACCanonicalFn func(d *HMACSigningData) (string, error)
+
+type SetHMACOptionFn func(o *hmacOptions)
+
+type hmacOptions struct {
+	hash            func() hash.Hash
+	template        string
+	canonicalFn     HMACCanonicalFn
+	signatureHeader string
+	signaturePrefix string
+	timestampHeader string
+	timestampFormat string
+	nonceHeader     string
+	encoding        SignatureEncoding
+	maxClockSkew    time.Duration
+	now             func() time.Time
+	nonce           func() string
+}
+
+// HMACHash set hash function. Default is sha256.New
+func HMACHash(fn func() hash.Hash) SetHMACOptionFn {
+	return func(o *hmacOptions) {
+		o.hash = fn
+	}
+}
+
+// HMACTemplate set canonical string template. Supported placeholders are {method}, {path}, {query} (sorted and escaped),
+// {timestamp}, {nonce}, {body_hash}, {host} and {header:Name}
+func HMACTemplate(t string) SetHMACOptionFn {
+	return func(o *hmacOptions) {
+		o.template = t
+	}
+}
+
+// HMACCanonical set function to compose canonical string. It takes precedence over HMACTemplate
+func HMACCanonical(fn HMACCanonicalFn) SetHMACOptionFn {
+	return func(o *hmacOptions) {
+		o.canonicalFn = fn
+	}
+}
+
+// HMACSignatureHeader set header name of signature and prefix of its value, e.g. "HMAC-SHA256 ". Default header is X-Signature
+func HMACSignatureHeader(name string, prefix string) SetHMACOptionFn {
+	return func(o *hmacOptions) {
+		o.signatureHeader = name
+		o.signaturePrefix = prefix
+	}
+}
+
+// HMACTimestampHeader send signing timestamp in header. Format is TimestampUnix, TimestampUnixMilli or time layout
+func HMACTimestampHeader(name string, format string) SetHMACOptionFn {
+	return func(o *hmacOptions) {
+		o.timestampHeader = name
+		o.timestampFormat = format
+	}
+}
+
+// HMACNonceHeader send random nonce in header
+func HMACNonceHeader(name string) SetHMACOptionFn {
+	return func(o *hmacOptions) {
+		o.nonceHeader = name
+	}
+}
+
+// HMACEncoding set encoding of signature and body hash. Default is SignatureHex
+func HMACEncoding(e SignatureEncoding) SetHMACOptionFn {
+	return func(o *hmacOptions) {
+		o.encoding = e
+	}
+}
+
+// HMACMaxClockSkew set maximum difference between timestamp header and current time accepted by Verify.
+// Only applies to unix timestamp formats
+func HMACMaxClockSkew(d time.Duration) SetHMACOptionFn {
+	return func(o *hmacOptions) {
+		o.maxClockSkew = d
+	}
+}
+
+// HMACClock override current time and nonce generator. Intended for deterministic tests
+func HMACClock(now func() time.Time, nonce func() string) SetHMACOptionFn {
+	return func(o *hmacOptions) {
+		if now != nil {
+			o.now = now
+		}
+		if nonce != nil {
+			o.nonce = nonce
+		}
+	}
+}
+
+// NewHMACSigner creates AuthProvider that sign request with HMAC signature computed over canonical string
+func NewHMACSigner(key []byte, args ...SetHMACOptionFn) *HMACSigner {
+	o := hmacOptions{
+		hash:            sha256.New,
+		template:        DefaultHMACTemplate,
+		signatureHeader: "X-Signature",
+		timestampFormat: TimestampUnix,
+		encoding:        SignatureHex,
+		maxClockSkew:    5 * time.Minute,
+		now:             time.Now,
+		nonce:           randomNonce,
+	}
+	for _, fn := range args {
+		fn(&o)
+	}
+	return &HMACSigner{key: key, o: &o}
+}
+
+type HMACSigner struct {
+	key []byte
+	o   *hmacOptions
+}
+
+// Authenticate sign request and set signature, timestamp and nonce headers
+func (s *HMACSigner) Authenticate(_ context.Context, req *http.Request, body []byte) error {
+	d := HMACSigningData{
+		Request: req,
+		Body:    body,
+		Nonce:   s.o.nonce(),
+	}
+	now := s.o.now()
+	switch s.o.timestampFormat {
+	case TimestampUnix:
+		d.Timestamp = strconv.FormatInt(now.Unix(), 10)
+	case TimestampUnixMilli:
+		d.Timestamp = strconv.FormatInt(now.UnixNano()/int64(time.Millisecond), 10)
+	default:
+		d.Timestamp = now.Format(s.o.timestampFormat)
+	}
+	// Set headers before signing, so it could be included in canonical string
+	if s.o.timestampHeader != "" {
+		req.Header.Set(s.o.timestampHeader, d.Timestamp)
+	}
+	if s.o.nonceHeader != "" {
+		req.Header.Set(s.o.nonceHeader, d.Nonce)
+	}
+	sig, err := s.Sign(&d)
+	if err != nil {
+		return err
+	}
+	req.Header.Set(s.o.signatureHeader, s.o.signaturePrefix+sig)
+	return nil
+}
+
+// Sign computes encoded signature of signing data
+func (s *HMACSigner) Sign(d *HMACSigningData) (string, error) {
+	h := s.o.hash()
+	h.Write(d.Body)
+	d.BodyHash = s.encode(h.Sum(nil))
+	// Compose canonical string
+	var cs string
+	var err error
+	if s.o.canonicalFn != nil {
+		cs, err = s.o.canonicalFn(d)
+	} else {
+		cs, err = s.canonicalString(d)
+	}
+	if err != nil {
+		return "", err
+	}
+	mac := hmac.New(s.o.hash, s.key)
+	mac.Write([]byte(cs))
+	return s.encode(mac.Sum(nil)), nil
+}
+
+// Verify validates signature of a request signed by HMACSigner with the same options. It reads timestamp and nonce
+// from request headers, so both must be configured if they are part of canonical string
+func (s *HMACSigner) Verify(req *http.Request, body []byte) error {
+	v := req.Header.Get(s.o.signatureHeader)
+	if v == "" || !strings.HasPrefix(v, s.o.signaturePrefix) {
+		return errors.New("httpc: Signature header is missing")
+	}
+	d := HMACSigningData{Request: req, Body: body}
+	if s.o.timestampHeader != "" {
+		d.Timestamp = req.Header.Get(s.o.timestampHeader)
+		if err := s.verifyTimestamp(d.Timestamp); err != nil {
+			return err
+		}
+	}
+	if s.o.nonceHeader != "" {
+		d.Nonce = req.Header.Get(s.o.nonceHeader)
+	}
+	expected, err := s.Sign(&d)
+	if err != nil {
+		return err
+	}
+	if !hmac.Equal([]byte(expected), []byte(strings.TrimPrefix(v, s.o.signaturePrefix))) {
+		return errors.New("httpc: Signature mismatch")
+	}
+	return nil
+}
+
+func (s *HMACSigner) verifyTimestamp(ts string) error {
+	var t time.Time
+	switch s.o.timestampFormat {
+	case TimestampUnix, TimestampUnixMilli:
+		n, err := strconv.ParseInt(ts, 10, 64)
+		if err != nil {
+			return fmt.Errorf("httpc: Invalid signature timestamp. Timestamp = %s", ts)
+		}
+		if s.o.timestampFormat == TimestampUnix {
+			t = time.Unix(n, 0)
+		} else {
+			t = time.Unix(0, n*int64(time.Millisecond))
+		}
+	default:
+		return nil
+	}
+	skew := s.o.now().Sub(t)
+	if skew < 0 {
+		skew = -skew
+	}
+	if s.o.maxClockSkew > 0 && skew > s.o.maxClockSkew {
+		return fmt.Errorf("httpc: Signature timestamp is out of allowed clock skew. Timestamp = %s", ts)
+	}
+	return nil
+}
+
+var hmacPlaceholderPattern = regexp.MustCompile(`\{([a-z_]+)(?::([^}]+))?}`)
+
+func (s *HMACSigner) canonicalString(d *HMACSigningData) (string, error) {
+	var err error
+	cs := hmacPlaceholderPattern.ReplaceAllStringFunc(s.o.template, func(m string) string {
+		g := hmacPlaceholderPattern.FindStringSubmatch(m)
+		switch g[1] {
+		case "method":
+			return d.Request.Method
+		case "path":
+			return d.Request.URL.EscapedPath()
+		case "query":
+			return sortedQuery(d.Request.URL.Query())
+		case "host":
+			// URL host is empty in server request
+			if d.Request.Host != "" {
+				return d.Request.Host
+			}
+			return d.Request.URL.Host
+		case "timestamp":
+			return d.Timestamp
+		case "nonce":
+			return d.Nonce
+		case "body_hash":
+			return d.BodyHash
+		case "header":
+			return strings.Join(d.Request.Header.Values(g[2]), ",")
+		}
+		if err == nil {
+			err = fmt.Errorf("httpc: Unknown placeholder in HMAC template. Placeholder = %s", m)
+		}
+		return m
+	})
+	if err != nil {
+		return "", err
+	}
+	return cs, nil
+}
+
+func (s *HMACSigner) encode(b []byte) string {
+	if s.o.encoding == SignatureBase64 {
+		return base64.StdEncoding.EncodeToString(b)
+	}
+	return hex.EncodeToString(b)
+}
+
+// sortedQuery encode query sorted by key and value
+func sortedQuery(q url.Values) string {
+	keys := make([]string, 0, len(q))
+	for k := range q {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	var b strings.Builder
+	for _, k := range keys {
+		values := append([]string(nil), q[k]...)
+		sort.Strings(values)
+		for _, v := range values {
+			if b.Len() > 0 {
+				b.WriteByte('&')
+			}
+			b.WriteString(url.QueryEscape(k))
+			b.WriteByte('=')
+			b.WriteString(url.QueryEscape(v))
+		}
+	}
+	return b.String()
+}
+
+func randomNonce() string {
+	b := make([]byte, 16)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
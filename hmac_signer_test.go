@@ -0,0 +1,104 @@
+package httpc_test
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha512"
+	"github.com/nbs-go/httpc"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestHMACSigner(t *testing.T) {
+	signer := httpc.NewHMACSigner([]byte("secret"),
+		httpc.HMACTimestampHeader("X-Timestamp", httpc.TimestampUnix),
+		httpc.HMACNonceHeader("X-Nonce"),
+		httpc.HMACSignatureHeader("Authorization", "HMAC-SHA256 "),
+	)
+	var verifyErr error
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		verifyErr = signer.Verify(r, body)
+	}))
+	defer srv.Close()
+	sc := httpc.MustNewClient(srv.URL, httpc.ClientAuth(signer))
+
+	_, err := httpc.NewRESTRequest(sc, "POST", "/payments").
+		AddQuery("b", "2", "a", "1").
+		Body(map[string]string{"amount": "1000"}).
+		Do(context.Background(), nil)
+	if err != nil {
+		t.Errorf("unexpected error: %s", err)
+		return
+	}
+	if verifyErr != nil {
+		t.Errorf("unexpected verify error: %s", verifyErr)
+	}
+}
+
+func TestHMACSignerTemplate(t *testing.T) {
+	now := func() time.Time { return time.Unix(1700000000, 0) }
+	nonce := func() string { return "abc" }
+	var canonical string
+	signer := httpc.NewHMACSigner([]byte("secret"),
+		httpc.HMACHash(sha512.New),
+		httpc.HMACEncoding(httpc.SignatureBase64),
+		httpc.HMACClock(now, nonce),
+		httpc.HMACTimestampHeader("X-Timestamp", httpc.TimestampUnix),
+		httpc.HMACNonceHeader("X-Nonce"),
+		httpc.HMACCanonical(func(d *httpc.HMACSigningData) (string, error) {
+			canonical = strings.Join([]string{d.Request.Method, d.Request.URL.Path, d.Timestamp, d.Nonce, d.BodyHash}, "|")
+			return canonical, nil
+		}),
+	)
+	req, _ := http.NewRequest("PUT", "https://api.nbs.dev/v1/orders", bytes.NewBufferString("{}"))
+	err := signer.Authenticate(context.Background(), req, []byte("{}"))
+	if err != nil {
+		t.Errorf("unexpected error: %s", err)
+		return
+	}
+	if !strings.HasPrefix(canonical, "PUT|/v1/orders|1700000000|abc|") {
+		t.Errorf("unexpected canonical string: %s", canonical)
+	}
+	if err = signer.Verify(req, []byte("{}")); err != nil {
+		t.Errorf("unexpected verify error: %s", err)
+	}
+	// Assert tampered body
+	if err = signer.Verify(req, []byte(`{"x":1}`)); err == nil || err.Error() != "httpc: Signature mismatch" {
+		t.Errorf("unexpected verify error: %v", err)
+	}
+	// Assert unknown placeholder
+	bad := httpc.NewHMACSigner([]byte("secret"), httpc.HMACTemplate("{method}\n{unknown}"))
+	err = bad.Authenticate(context.Background(), req, nil)
+	if err == nil || err.Error() != "httpc: Unknown placeholder in HMAC template. Placeholder = {unknown}" {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
+func TestHMACVerifyHost(t *testing.T) {
+	signer := httpc.NewHMACSigner([]byte("secret"),
+		httpc.HMACTemplate("{method}\n{host}\n{path}\n{timestamp}\n{nonce}\n{body_hash}"),
+		httpc.HMACTimestampHeader("X-Timestamp", httpc.TimestampUnix),
+		httpc.HMACNonceHeader("X-Nonce"),
+	)
+	var verifyErr error
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		verifyErr = signer.Verify(r, body)
+	}))
+	defer srv.Close()
+	sc := httpc.MustNewClient(srv.URL, httpc.ClientAuth(signer))
+
+	_, _, err := sc.DoRequest(context.Background(), "POST", "/payments", httpc.SetJsonBody(map[string]string{"a": "1"}))
+	if err != nil {
+		t.Errorf("unexpected error: %s", err)
+		return
+	}
+	if verifyErr != nil {
+		t.Errorf("unexpected verify error: %s", verifyErr)
+	}
+}
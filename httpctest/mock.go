@@ -0,0 +1,356 @@
+// Package httpctest provides a programmable mock transport to test code that use httpc.Client without network access
+package httpctest
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"path"
+	"reflect"
+	"strings"
+	"sync"
+	"time"
+)
+
+// TestingT is the subset of testing.T used in assertions
+type TestingT interface {
+	Helper()
+	Errorf(format string, args ...interface{})
+}
+
+// NewMockTransport creates an http.RoundTripper that returns canned responses of matched expectations.
+// Use it with httpc.Transport client option
+func NewMockTransport() *MockTransport {
+	return &MockTransport{}
+}
+
+// MockTransport matches requests with expectations in the order they are registered. Request that does not match
+// any expectation fails with error
+type MockTransport struct {
+	mu           sync.Mutex
+	expectations []*Expectation
+	calls        []*Call
+}
+
+// Call is a request received by MockTransport
+type Call struct {
+	Request *http.Request
+	Body    []byte
+	// Expectation is the matched expectation, or nil if request is unmatched
+	Expectation *Expectation
+}
+
+// On registers expectation of request with method and path. Path could contain wildcards as in path.Match, empty
+// method or path matches any value. By default, expectation is expected to be called once and reply 200 OK
+func (m *MockTransport) On(method string, path string) *Expectation {
+	e := &Expectation{
+		method:   method,
+		path:     path,
+		query:    make(url.Values),
+		header:   make(http.Header),
+		times:    1,
+		response: &MockResponse{status: http.StatusOK, header: make(http.Header)},
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.expectations = append(m.expectations, e)
+	return e
+}
+
+func (m *MockTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	var body []byte
+	if req.Body != nil {
+		var err error
+		body, err = io.ReadAll(req.Body)
+		_ = req.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+	}
+	// Find expectation
+	m.mu.Lock()
+	var matched *Expectation
+	for _, e := range m.expectations {
+		if !e.exhausted() && e.matches(req, body) {
+			matched = e
+			e.calls++
+			break
+		}
+	}
+	m.calls = append(m.calls, &Call{Request: req, Body: body, Expectation: matched})
+	m.mu.Unlock()
+	if matched == nil {
+		return nil, fmt.Errorf("httpctest: No expectation matched request. Method = %s, Url = %s", req.Method, req.URL)
+	}
+	// Simulate latency
+	if matched.delay > 0 {
+		t := time.NewTimer(matched.delay)
+		select {
+		case <-t.C:
+		case <-req.Context().Done():
+			t.Stop()
+			return nil, req.Context().Err()
+		}
+	}
+	if matched.err != nil {
+		return nil, matched.err
+	}
+	return matched.response.build(req)
+}
+
+// Handler returns http.Handler that serves requests using expectations, e.g. to use with httptest.NewServer.
+// Unmatched request is responded with 501 Not Implemented
+func (m *MockTransport) Handler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		resp, err := m.RoundTrip(r)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusNotImplemented)
+			return
+		}
+		defer func() { _ = resp.Body.Close() }()
+		for k, v := range resp.Header {
+			w.Header()[k] = v
+		}
+		w.WriteHeader(resp.StatusCode)
+		_, _ = io.Copy(w, resp.Body)
+	})
+}
+
+// Calls returns received requests
+func (m *MockTransport) Calls() []*Call {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]*Call(nil), m.calls...)
+}
+
+// UnmatchedCalls returns received requests that do not match any expectation
+func (m *MockTransport) UnmatchedCalls() []*Call {
+	var result []*Call
+	for _, c := range m.Calls() {
+		if c.Expectation == nil {
+			result = append(result, c)
+		}
+	}
+	return result
+}
+
+// AssertExpectations asserts there is no unmatched request and every expectation is called as many times as expected
+func (m *MockTransport) AssertExpectations(t TestingT) bool {
+	t.Helper()
+	ok := true
+	for _, c := range m.UnmatchedCalls() {
+		t.Errorf("httpctest: Unexpected request. Method = %s, Url = %s", c.Request.Method, c.Request.URL)
+		ok = false
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, e := range m.expectations {
+		if e.times > 0 && e.calls < e.times {
+			t.Errorf("httpctest: Expectation is not met. Expectation = %s, Expected = %d, Actual = %d", e, e.times, e.calls)
+			ok = false
+		}
+	}
+	return ok
+}
+
+// AssertCalled asserts number of requests that match method and path. Path could contain wildcards as in path.Match
+func (m *MockTransport) AssertCalled(t TestingT, method string, path string, times int) bool {
+	t.Helper()
+	n := 0
+	for _, c := range m.Calls() {
+		if matchMethodPath(method, path, c.Request) {
+			n++
+		}
+	}
+	if n != times {
+		t.Errorf("httpctest: Unexpected number of calls. Request = %s %s, Expected = %d, Actual = %d", method, path, times, n)
+		return false
+	}
+	return true
+}
+
+// Expectation is an expected request and the reply
+type Expectation struct {
+	method   string
+	path     string
+	query    url.Values
+	header   http.Header
+	jsonBody interface{}
+	hasJson  bool
+	matchers []func(req *http.Request, body []byte) bool
+	times    int
+	calls    int
+	delay    time.Duration
+	err      error
+	response *MockResponse
+}
+
+// Query matches query parameter values
+func (e *Expectation) Query(key string, values ...string) *Expectation {
+	e.query[key] = values
+	return e
+}
+
+// Header matches request that contains header value
+func (e *Expectation) Header(key string, value string) *Expectation {
+	e.header.Add(key, value)
+	return e
+}
+
+// JSONBody matches request body that is equal to v when both are encoded as JSON
+func (e *Expectation) JSONBody(v interface{}) *Expectation {
+	e.jsonBody = v
+	e.hasJson = true
+	return e
+}
+
+// Match matches request using custom function
+func (e *Expectation) Match(fn func(req *http.Request, body []byte) bool) *Expectation {
+	e.matchers = append(e.matchers, fn)
+	return e
+}
+
+// Times set number of times expectation is expected to be called. Expectation no longer matches after called n times
+func (e *Expectation) Times(n int) *Expectation {
+	e.times = n
+	return e
+}
+
+// AnyTimes set expectation to match any number of times, including zero
+func (e *Expectation) AnyTimes() *Expectation {
+	e.times = 0
+	return e
+}
+
+// Delay simulates latency before reply. Request context cancellation is respected
+func (e *Expectation) Delay(d time.Duration) *Expectation {
+	e.delay = d
+	return e
+}
+
+// ReplyError returns err from transport, e.g. to simulate connection error
+func (e *Expectation) ReplyError(err error) *Expectation {
+	e.err = err
+	return e
+}
+
+// Reply set response status and returns MockResponse to set header and body
+func (e *Expectation) Reply(status int) *MockResponse {
+	e.response.status = status
+	return e.response
+}
+
+func (e *Expectation) String() string {
+	return strings.TrimSpace(e.method + " " + e.path)
+}
+
+func (e *Expectation) exhausted() bool {
+	return e.times > 0 && e.calls >= e.times
+}
+
+func (e *Expectation) matches(req *http.Request, body []byte) bool {
+	if !matchMethodPath(e.method, e.path, req) {
+		return false
+	}
+	q := req.URL.Query()
+	for k, v := range e.query {
+		if !reflect.DeepEqual(q[k], v) {
+			return false
+		}
+	}
+	for k, values := range e.header {
+		for _, v := range values {
+			if !contains(req.Header.Values(k), v) {
+				return false
+			}
+		}
+	}
+	if e.hasJson && !jsonEqual(e.jsonBody, body) {
+		return false
+	}
+	for _, fn := range e.matchers {
+		if !fn(req, body) {
+			return false
+		}
+	}
+	return true
+}
+
+// MockResponse is a canned response
+type MockResponse struct {
+	status int
+	header http.Header
+	body   []byte
+	err    error
+}
+
+// Header adds response header
+func (r *MockResponse) Header(key string, value string) *MockResponse {
+	r.header.Add(key, value)
+	return r
+}
+
+// Body set response body
+func (r *MockResponse) Body(body string) *MockResponse {
+	r.body = []byte(body)
+	return r
+}
+
+// JSON set v encoded as JSON as response body and set Content-Type header
+func (r *MockResponse) JSON(v interface{}) *MockResponse {
+	r.body, r.err = json.Marshal(v)
+	r.header.Set("Content-Type", "application/json")
+	return r
+}
+
+func (r *MockResponse) build(req *http.Request) (*http.Response, error) {
+	if r.err != nil {
+		return nil, fmt.Errorf("httpctest: Failed to encode response body. Error = %w", r.err)
+	}
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", r.status, http.StatusText(r.status)),
+		StatusCode:    r.status,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        r.header.Clone(),
+		Body:          io.NopCloser(bytes.NewReader(r.body)),
+		ContentLength: int64(len(r.body)),
+		Request:       req,
+	}, nil
+}
+
+func matchMethodPath(method string, pattern string, req *http.Request) bool {
+	if method != "" && !strings.EqualFold(method, req.Method) {
+		return false
+	}
+	if pattern == "" {
+		return true
+	}
+	ok, _ := path.Match(pattern, req.URL.Path)
+	return ok
+}
+
+func jsonEqual(expected interface{}, body []byte) bool {
+	b, err := json.Marshal(expected)
+	if err != nil {
+		return false
+	}
+	var e, a interface{}
+	if json.Unmarshal(b, &e) != nil || json.Unmarshal(body, &a) != nil {
+		return false
+	}
+	return reflect.DeepEqual(e, a)
+}
+
+func contains(values []string, s string) bool {
+	for _, v := range values {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,110 @@
+package httpctest_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/nbs-go/httpc"
+	"github.com/nbs-go/httpc/httpctest"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+// recordT is a httpctest.TestingT that records errors
+type recordT struct {
+	errors []string
+}
+
+func (t *recordT) Helper() {}
+
+func (t *recordT) Errorf(format string, args ...interface{}) {
+	t.errors = append(t.errors, fmt.Sprintf(format, args...))
+}
+
+func TestMockTransport(t *testing.T) {
+	m := httpctest.NewMockTransport()
+	m.On("POST", "/users").Header("X-Tenant", "a").JSONBody(map[string]interface{}{"name": "john"}).
+		Reply(http.StatusCreated).JSON(map[string]interface{}{"id": 1})
+	m.On("GET", "/users/*").Query("fields", "name").AnyTimes().Reply(http.StatusOK).Body(`{"name":"john"}`)
+	rc := httpc.MustNewClient("https://api.example.com", httpc.Transport(m))
+
+	resp, body, err := rc.DoRequest(context.Background(), "POST", "/users", httpc.AddHeader("X-Tenant", "a"),
+		httpc.SetJsonBody(map[string]string{"name": "john"}))
+	if err != nil {
+		t.Errorf("unexpected error: %s", err)
+		return
+	}
+	if resp.StatusCode != http.StatusCreated || string(body) != `{"id":1}` ||
+		resp.Header.Get(httpc.HeaderContentType) != httpc.MimeTypeJson {
+		t.Errorf("unexpected response. Status = %d, Body = %s", resp.StatusCode, body)
+	}
+	for i := 0; i < 2; i++ {
+		_, body, err = rc.DoRequest(context.Background(), "GET", "/users/{id}", httpc.PathParam("id", "1"),
+			httpc.AddQuery("fields", "name"))
+		if err != nil || string(body) != `{"name":"john"}` {
+			t.Errorf("unexpected response. Body = %s, Error = %v", body, err)
+		}
+	}
+	m.AssertExpectations(t)
+	m.AssertCalled(t, "GET", "/users/*", 2)
+}
+
+func TestMockTransportUnmatched(t *testing.T) {
+	m := httpctest.NewMockTransport()
+	m.On("POST", "/users").JSONBody(map[string]interface{}{"name": "john"})
+	m.On("GET", "/unused")
+	rc := httpc.MustNewClient("https://api.example.com", httpc.Transport(m))
+
+	_, _, err := rc.DoRequest(context.Background(), "POST", "/users", httpc.SetJsonBody(map[string]string{"name": "jane"}))
+	if err == nil {
+		t.Errorf("expected error is not returned")
+	}
+	rt := new(recordT)
+	if m.AssertExpectations(rt) || len(rt.errors) != 3 {
+		t.Errorf("unexpected assertion errors: %v", rt.errors)
+	}
+	if len(m.UnmatchedCalls()) != 1 || string(m.UnmatchedCalls()[0].Body) != `{"name":"jane"}` {
+		t.Errorf("unexpected unmatched calls: %v", m.UnmatchedCalls())
+	}
+}
+
+func TestMockTransportDelayAndError(t *testing.T) {
+	m := httpctest.NewMockTransport()
+	m.On("GET", "/slow").Delay(time.Second)
+	connErr := errors.New("connection reset")
+	m.On("GET", "/error").ReplyError(connErr)
+	rc := httpc.MustNewClient("https://api.example.com", httpc.Transport(m))
+
+	start := time.Now()
+	_, _, err := rc.DoRequest(context.Background(), "GET", "/slow", httpc.Timeout(50))
+	if !errors.Is(err, context.DeadlineExceeded) || time.Since(start) > 500*time.Millisecond {
+		t.Errorf("unexpected error: %v", err)
+	}
+	_, _, err = rc.DoRequest(context.Background(), "GET", "/error")
+	if !errors.Is(err, connErr) {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
+func TestMockTransportHandler(t *testing.T) {
+	m := httpctest.NewMockTransport()
+	m.On("GET", "/hello").Reply(http.StatusAccepted).Header("X-Id", "1").Body("hello")
+	srv := httptest.NewServer(m.Handler())
+	defer srv.Close()
+	rc := httpc.MustNewClient(srv.URL)
+
+	resp, body, err := rc.DoRequest(context.Background(), "GET", "/hello")
+	if err != nil {
+		t.Errorf("unexpected error: %s", err)
+		return
+	}
+	if resp.StatusCode != http.StatusAccepted || resp.Header.Get("X-Id") != "1" || string(body) != "hello" {
+		t.Errorf("unexpected response. Status = %d, Body = %s", resp.StatusCode, body)
+	}
+	resp, _, _ = rc.DoRequest(context.Background(), "GET", "/other")
+	if resp.StatusCode != http.StatusNotImplemented {
+		t.Errorf("unexpected status of unmatched request: %d", resp.StatusCode)
+	}
+}
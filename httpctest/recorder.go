@@ -0,0 +1,355 @@
+package httpctest
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+)
+
+// Mode is Recorder mode
+type Mode int8
+
+const (
+	// ModeReplay replays recorded interactions. Unmatched request fails with error
+	ModeReplay Mode = iota
+	// ModeRecord sends every request to upstream and records the interaction, existing cassette is overwritten
+	ModeRecord
+	// ModeReplayOrRecord replays recorded interactions and records unmatched request
+	ModeReplayOrRecord
+	// ModePassthrough sends every request to upstream without recording
+	ModePassthrough
+)
+
+// CassetteVersion is the version of cassette file format
+const CassetteVersion = 1
+
+// RedactionMask is the replacement of redacted values in cassette
+const RedactionMask = "***"
+
+// Cassette is recorded interactions that is stored as JSON file
+type Cassette struct {
+	Version      int            `json:"version"`
+	Interactions []*Interaction `json:"interactions"`
+}
+
+// Interaction is a recorded request and response
+type Interaction struct {
+	Request  RecordedRequest  `json:"request"`
+	Response RecordedResponse `json:"response"`
+	used     bool
+}
+
+type RecordedRequest struct {
+	Method string      `json:"method"`
+	Url    string      `json:"url"`
+	Header http.Header `json:"header,omitempty"`
+	Body   Body        `json:"body"`
+}
+
+type RecordedResponse struct {
+	StatusCode int           `json:"statusCode"`
+	Header     http.Header   `json:"header,omitempty"`
+	Body       Body          `json:"body"`
+	Duration   time.Duration `json:"duration"`
+}
+
+// Body is recorded body. Body that is not a valid UTF-8 text is encoded in base64
+type Body []byte
+
+func (b Body) MarshalJSON() ([]byte, error) {
+	if utf8.Valid(b) {
+		return json.Marshal(map[string]string{"text": string(b)})
+	}
+	return json.Marshal(map[string]string{"base64": base64.StdEncoding.EncodeToString(b)})
+}
+
+func (b *Body) UnmarshalJSON(data []byte) error {
+	var v map[string]string
+	err := json.Unmarshal(data, &v)
+	if err != nil {
+		return err
+	}
+	if s, ok := v["base64"]; ok {
+		*b, err = base64.StdEncoding.DecodeString(s)
+		return err
+	}
+	*b = Body(v["text"])
+	return nil
+}
+
+// Matcher returns true if request matches recorded request. Incoming request is redacted before matched
+type Matcher func(r *RecordedRequest, recorded *RecordedRequest) bool
+
+// MatchMethod matches request method
+func MatchMethod(r *RecordedRequest, recorded *RecordedRequest) bool {
+	return r.Method == recorded.Method
+}
+
+// MatchUrl matches request url, including query
+func MatchUrl(r *RecordedRequest, recorded *RecordedRequest) bool {
+	return r.Url == recorded.Url
+}
+
+// MatchBody matches request body
+func MatchBody(r *RecordedRequest, recorded *RecordedRequest) bool {
+	return bytes.Equal(r.Body, recorded.Body)
+}
+
+// MatchHeader returns Matcher that matches values of header keys
+func MatchHeader(keys ...string) Matcher {
+	return func(r *RecordedRequest, recorded *RecordedRequest) bool {
+		for _, k := range keys {
+			if strings.Join(r.Header.Values(k), ",") != strings.Join(recorded.Header.Values(k), ",") {
+				return false
+			}
+		}
+		return true
+	}
+}
+
+type SetRecorderOptionFn func(r *Recorder)
+
+// RecorderTransport set transport to send request to upstream. Default is http.DefaultTransport
+func RecorderTransport(rt http.RoundTripper) SetRecorderOptionFn {
+	return func(r *Recorder) {
+		r.transport = rt
+	}
+}
+
+// RecorderMatchers set matchers, request matches an interaction if all matchers return true.
+// Default matchers are MatchMethod and MatchUrl
+func RecorderMatchers(matchers ...Matcher) SetRecorderOptionFn {
+	return func(r *Recorder) {
+		r.matchers = matchers
+	}
+}
+
+// RedactHeaders set header keys which values are masked before saved. By default, Authorization,
+// Proxy-Authorization, Cookie and Set-Cookie are redacted
+func RedactHeaders(keys ...string) SetRecorderOptionFn {
+	return func(r *Recorder) {
+		r.redactHeaders = keys
+	}
+}
+
+// RedactQuery set query parameter names which values are masked before saved
+func RedactQuery(names ...string) SetRecorderOptionFn {
+	return func(r *Recorder) {
+		r.redactQuery = names
+	}
+}
+
+// BeforeSave set function to modify interaction before saved, e.g. to redact secrets in body
+func BeforeSave(fn func(i *Interaction)) SetRecorderOptionFn {
+	return func(r *Recorder) {
+		r.beforeSave = fn
+	}
+}
+
+// NewRecorder creates an http.RoundTripper that records and replays interactions using cassette file in path.
+// In ModeReplay, cassette file must exist. Call Stop to save recorded interactions
+func NewRecorder(path string, mode Mode, args ...SetRecorderOptionFn) (*Recorder, error) {
+	r := Recorder{
+		path:          path,
+		mode:          mode,
+		transport:     http.DefaultTransport,
+		matchers:      []Matcher{MatchMethod, MatchUrl},
+		redactHeaders: []string{"Authorization", "Proxy-Authorization", "Cookie", "Set-Cookie"},
+		cassette:      &Cassette{Version: CassetteVersion},
+	}
+	for _, fn := range args {
+		fn(&r)
+	}
+	if mode == ModeRecord || mode == ModePassthrough {
+		return &r, nil
+	}
+	// Load cassette
+	b, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) && mode == ModeReplayOrRecord {
+		return &r, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("httpctest: Failed to read cassette. Path = %s, Error = %w", path, err)
+	}
+	err = json.Unmarshal(b, r.cassette)
+	if err != nil {
+		return nil, fmt.Errorf("httpctest: Failed to decode cassette. Path = %s, Error = %w", path, err)
+	}
+	return &r, nil
+}
+
+// Recorder is an http.RoundTripper that records and replays interactions
+type Recorder struct {
+	path          string
+	mode          Mode
+	transport     http.RoundTripper
+	matchers      []Matcher
+	redactHeaders []string
+	redactQuery   []string
+	beforeSave    func(i *Interaction)
+	mu            sync.Mutex
+	cassette      *Cassette
+	recorded      bool
+}
+
+func (r *Recorder) RoundTrip(req *http.Request) (*http.Response, error) {
+	if r.mode == ModePassthrough {
+		return r.transport.RoundTrip(req)
+	}
+	var body []byte
+	if req.Body != nil {
+		var err error
+		body, err = io.ReadAll(req.Body)
+		_ = req.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+	}
+	rr := r.recordRequest(req, body)
+	// Replay
+	if r.mode != ModeRecord {
+		if i := r.find(rr); i != nil {
+			return i.Response.build(req), nil
+		}
+		if r.mode == ModeReplay {
+			return nil, fmt.Errorf("httpctest: No recorded interaction matched request in replay mode. Method = %s, Url = %s, Cassette = %s",
+				rr.Method, rr.Url, r.path)
+		}
+	}
+	// Send request to upstream and record interaction
+	out := req.Clone(req.Context())
+	if req.Body != nil {
+		out.Body = io.NopCloser(bytes.NewReader(body))
+	}
+	start := time.Now()
+	resp, err := r.transport.RoundTrip(out)
+	if err != nil {
+		return nil, err
+	}
+	respBody, err := io.ReadAll(resp.Body)
+	_ = resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(respBody))
+	header := resp.Header.Clone()
+	r.redactHeader(header)
+	i := Interaction{
+		Request: *rr,
+		Response: RecordedResponse{
+			StatusCode: resp.StatusCode,
+			Header:     header,
+			Body:       respBody,
+			Duration:   time.Since(start),
+		},
+		used: true,
+	}
+	if r.beforeSave != nil {
+		r.beforeSave(&i)
+	}
+	r.mu.Lock()
+	r.cassette.Interactions = append(r.cassette.Interactions, &i)
+	r.recorded = true
+	r.mu.Unlock()
+	return resp, nil
+}
+
+// Stop saves cassette if there is a recorded interaction
+func (r *Recorder) Stop() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if !r.recorded {
+		return nil
+	}
+	b, err := json.MarshalIndent(r.cassette, "", "  ")
+	if err != nil {
+		return fmt.Errorf("httpctest: Failed to encode cassette. Error = %w", err)
+	}
+	err = os.MkdirAll(filepath.Dir(r.path), 0755)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(r.path, b, 0644)
+}
+
+// find returns the first matched interaction that has not been replayed, or the last matched interaction if all
+// matched interactions have been replayed
+func (r *Recorder) find(rr *RecordedRequest) *Interaction {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var last *Interaction
+	for _, i := range r.cassette.Interactions {
+		if !r.matches(rr, &i.Request) {
+			continue
+		}
+		if !i.used {
+			i.used = true
+			return i
+		}
+		last = i
+	}
+	return last
+}
+
+func (r *Recorder) matches(rr *RecordedRequest, recorded *RecordedRequest) bool {
+	for _, m := range r.matchers {
+		if !m(rr, recorded) {
+			return false
+		}
+	}
+	return true
+}
+
+// recordRequest returns redacted request
+func (r *Recorder) recordRequest(req *http.Request, body []byte) *RecordedRequest {
+	u := *req.URL
+	if len(r.redactQuery) > 0 && u.RawQuery != "" {
+		q := u.Query()
+		for _, k := range r.redactQuery {
+			if _, ok := q[k]; ok {
+				q[k] = []string{RedactionMask}
+			}
+		}
+		u.RawQuery = q.Encode()
+	}
+	header := req.Header.Clone()
+	r.redactHeader(header)
+	return &RecordedRequest{
+		Method: req.Method,
+		Url:    u.String(),
+		Header: header,
+		Body:   body,
+	}
+}
+
+func (r *Recorder) redactHeader(h http.Header) {
+	for _, k := range r.redactHeaders {
+		if values := h.Values(k); len(values) > 0 {
+			h.Set(k, RedactionMask)
+		}
+	}
+}
+
+func (rr *RecordedResponse) build(req *http.Request) *http.Response {
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", rr.StatusCode, http.StatusText(rr.StatusCode)),
+		StatusCode:    rr.StatusCode,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        rr.Header.Clone(),
+		Body:          io.NopCloser(bytes.NewReader(rr.Body)),
+		ContentLength: int64(len(rr.Body)),
+		Request:       req,
+	}
+}
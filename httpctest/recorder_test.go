@@ -0,0 +1,112 @@
+package httpctest_test
+
+import (
+	"context"
+	"github.com/nbs-go/httpc"
+	"github.com/nbs-go/httpc/httpctest"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestRecorder(t *testing.T) {
+	calls := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.Header().Set("X-Path", r.URL.Path)
+		_, _ = w.Write([]byte("hello " + r.URL.Query().Get("name")))
+	}))
+	defer srv.Close()
+	cassette := filepath.Join(t.TempDir(), "fixtures", "hello.json")
+
+	// Record
+	rec, err := httpctest.NewRecorder(cassette, httpctest.ModeRecord, httpctest.RedactQuery("api_key"))
+	if err != nil {
+		t.Errorf("unexpected error: %s", err)
+		return
+	}
+	rc := httpc.MustNewClient(srv.URL, httpc.Transport(rec), httpc.ClientBearerToken("s3cret-token"))
+	_, body, err := rc.DoRequest(context.Background(), "GET", "/hello", httpc.AddQuery("name", "john", "api_key", "s3cret-key"))
+	if err != nil || string(body) != "hello john" {
+		t.Errorf("unexpected response. Body = %s, Error = %v", body, err)
+		return
+	}
+	if err = rec.Stop(); err != nil {
+		t.Errorf("unexpected error: %s", err)
+		return
+	}
+	b, _ := os.ReadFile(cassette)
+	if strings.Contains(string(b), "s3cret") {
+		t.Errorf("unexpected condition: secret is saved in cassette.\n%s", b)
+	}
+
+	// Replay
+	srv.Close()
+	rec, err = httpctest.NewRecorder(cassette, httpctest.ModeReplay)
+	if err != nil {
+		t.Errorf("unexpected error: %s", err)
+		return
+	}
+	rc = httpc.MustNewClient(srv.URL, httpc.Transport(rec))
+	resp, body, err := rc.DoRequest(context.Background(), "GET", "/hello", httpc.AddQuery("name", "john", "api_key", "another-key"))
+	if err == nil {
+		t.Errorf("expected error is not returned. Query is redacted only with RedactQuery option")
+	}
+	rec, _ = httpctest.NewRecorder(cassette, httpctest.ModeReplay, httpctest.RedactQuery("api_key"))
+	rc = httpc.MustNewClient(srv.URL, httpc.Transport(rec))
+	resp, body, err = rc.DoRequest(context.Background(), "GET", "/hello", httpc.AddQuery("name", "john", "api_key", "another-key"))
+	if err != nil || string(body) != "hello john" || resp.Header.Get("X-Path") != "/hello" {
+		t.Errorf("unexpected replayed response. Body = %s, Error = %v", body, err)
+	}
+	_, _, err = rc.DoRequest(context.Background(), "GET", "/other")
+	if err == nil || !strings.Contains(err.Error(), "No recorded interaction matched request in replay mode") {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if calls != 1 {
+		t.Errorf("unexpected upstream calls: %d", calls)
+	}
+}
+
+func TestRecorderReplayOrRecord(t *testing.T) {
+	calls := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		_, _ = w.Write([]byte{0xff, 0x00, byte(calls)})
+	}))
+	defer srv.Close()
+	cassette := filepath.Join(t.TempDir(), "binary.json")
+
+	for i := 0; i < 2; i++ {
+		rec, err := httpctest.NewRecorder(cassette, httpctest.ModeReplayOrRecord,
+			httpctest.RecorderMatchers(httpctest.MatchMethod, httpctest.MatchUrl, httpctest.MatchBody))
+		if err != nil {
+			t.Errorf("unexpected error: %s", err)
+			return
+		}
+		rc := httpc.MustNewClient(srv.URL, httpc.Transport(rec))
+		_, body, err := rc.DoRequest(context.Background(), "POST", "/bin", httpc.SetBody([]byte("a")))
+		if err != nil || string(body) != "\xff\x00\x01" {
+			t.Errorf("unexpected response. Body = %v, Error = %v", body, err)
+		}
+		_, body, err = rc.DoRequest(context.Background(), "POST", "/bin", httpc.SetBody([]byte("b")))
+		if err != nil || string(body) != "\xff\x00\x02" {
+			t.Errorf("unexpected response. Body = %v, Error = %v", body, err)
+		}
+		if err = rec.Stop(); err != nil {
+			t.Errorf("unexpected error: %s", err)
+		}
+	}
+	if calls != 2 {
+		t.Errorf("unexpected upstream calls: %d", calls)
+	}
+}
+
+func TestRecorderMissingCassette(t *testing.T) {
+	_, err := httpctest.NewRecorder(filepath.Join(t.TempDir(), "missing.json"), httpctest.ModeReplay)
+	if err == nil {
+		t.Errorf("expected error is not returned")
+	}
+}
@@ -0,0 +1,182 @@
+package httpc
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	logOption "github.com/nbs-go/nlogger/v2/option"
+	"io"
+	"mime"
+	"net/http"
+	"net/http/httputil"
+	"strings"
+	"unicode/utf8"
+)
+
+// DumpLevel defines what is written in log dump
+type DumpLevel int8
+
+const (
+	// DumpOff disables log dump
+	DumpOff DumpLevel = iota
+	// DumpHeaders dumps request line, status line and headers
+	DumpHeaders
+	// DumpBody dumps headers and body
+	DumpBody
+)
+
+// DumpConfig configures HTTP request and response log dump
+type DumpConfig struct {
+	Level DumpLevel
+	// MaxBodySize is the maximum dumped body size in bytes, the rest of body is truncated. Zero value means no limit
+	MaxBodySize int
+	// PrettyJSON indents JSON body
+	PrettyJSON bool
+	// OnError only dumps request that failed or responded with 4xx or 5xx status
+	OnError bool
+	// StatusClasses only dumps request that responded with listed status classes, e.g. 3 for 3xx. Request that failed
+	// is always dumped if OnError or StatusClasses is set
+	StatusClasses []int
+}
+
+// conditional returns true if dump depends on response
+func (dc *DumpConfig) conditional() bool {
+	return dc.OnError || len(dc.StatusClasses) > 0
+}
+
+// shouldDump returns true if response with status code should be dumped
+func (dc *DumpConfig) shouldDump(statusCode int) bool {
+	if !dc.conditional() {
+		return true
+	}
+	if dc.OnError && statusCode >= 400 {
+		return true
+	}
+	for _, sc := range dc.StatusClasses {
+		if statusCode/100 == sc {
+			return true
+		}
+	}
+	return false
+}
+
+// resolveDumpConfig returns dump config of request, or nil if dump is disabled
+func (c *Client) resolveDumpConfig(o *requestOptions) *DumpConfig {
+	dc := c.dump
+	if o.dump != nil {
+		dc = o.dump
+	}
+	if dc == nil || dc.Level == DumpOff {
+		return nil
+	}
+	return dc
+}
+
+func (c *Client) dumpRequest(ctx context.Context, dc *DumpConfig, req *http.Request, reqBody []byte, sent bool) []byte {
+	// Dump a copy of request, so sensitive data could be redacted without modifying request
+	dr := c.cloneWithCookies(ctx, req, sent)
+	c.redactor.redactHeader(dr.Header)
+	dr.URL = c.redactor.redactUrl(dr.URL)
+	dr.Body = nil
+	dump, err := httputil.DumpRequest(dr, false)
+	if err != nil {
+		c.log.Warn("Unable to dump request. Error = %s", logOption.Format(err), logOption.Context(ctx))
+		return nil
+	}
+	if dc.Level == DumpBody {
+		dump = append(dump, c.dumpBody(dc, dr.Header.Get(HeaderContentType), reqBody)...)
+	}
+	return c.redactor.redactText(dump)
+}
+
+// dumpResponse returns redacted response dump. Error is returned if response body could not be read
+func (c *Client) dumpResponse(ctx context.Context, dc *DumpConfig, resp *http.Response) ([]byte, error) {
+	var respBody []byte
+	if dc.Level == DumpBody {
+		// Read response body and set it back to response, so it could be read by caller
+		var err error
+		respBody, err = io.ReadAll(resp.Body)
+		if err != nil {
+			return nil, err
+		}
+		_ = resp.Body.Close()
+		resp.Body = io.NopCloser(bytes.NewReader(respBody))
+	}
+	// Dump a copy of response with redacted sensitive data
+	dr := *resp
+	dr.Header = resp.Header.Clone()
+	c.redactor.redactHeader(dr.Header)
+	dr.Body = nil
+	dump, err := httputil.DumpResponse(&dr, false)
+	if err != nil {
+		c.log.Warn("Unable to dump response. Error = %s", logOption.Format(err), logOption.Context(ctx))
+		return nil, nil
+	}
+	if dc.Level == DumpBody {
+		dump = append(dump, c.dumpBody(dc, dr.Header.Get(HeaderContentType), respBody)...)
+	}
+	return c.redactor.redactText(dump), nil
+}
+
+// dumpBody returns redacted body for dump. Binary body is omitted, JSON body could be indented and body that exceeds
+// MaxBodySize is truncated
+func (c *Client) dumpBody(dc *DumpConfig, contentType string, body []byte) []byte {
+	if len(body) == 0 {
+		return nil
+	}
+	if !isTextBody(contentType, body) {
+		return []byte(fmt.Sprintf("[Binary body omitted. Length = %d, Content-Type = %s]", len(body), contentType))
+	}
+	body = c.redactor.redactBody(contentType, body)
+	if dc.PrettyJSON && isJsonContentType(contentType) {
+		var b bytes.Buffer
+		if err := json.Indent(&b, body, "", "  "); err == nil {
+			body = b.Bytes()
+		}
+	}
+	if dc.MaxBodySize > 0 && len(body) > dc.MaxBodySize {
+		// Back up to rune boundary, so multibyte character is not split
+		n := dc.MaxBodySize
+		for n > 0 && !utf8.RuneStart(body[n]) {
+			n--
+		}
+		truncated := len(body) - n
+		body = append(body[:n:n], fmt.Sprintf("\n[Truncated %d bytes]", truncated)...)
+	}
+	return body
+}
+
+func (c *Client) logDumpRequest(ctx context.Context, dump []byte, reqId string) {
+	c.log.Debug("\n---------- HTTP Request Dump -----------\n(RequestId=%s)\n%s\n----------------------------------------",
+		logOption.Format(reqId, dump), logOption.Context(ctx),
+	)
+}
+
+func (c *Client) logDumpResponse(ctx context.Context, dump []byte, reqId string) {
+	c.log.Debug("\n---------- HTTP Response Dump ----------\n(RequestId=%s)\n%s\n----------------------------------------",
+		logOption.Format(reqId, dump), logOption.Context(ctx),
+	)
+}
+
+// isTextBody returns true if body is a text based on content type, or detected from body if content type is not set
+func isTextBody(contentType string, body []byte) bool {
+	if contentType == "" {
+		contentType = http.DetectContentType(body)
+	}
+	mt, _, _ := mime.ParseMediaType(contentType)
+	switch {
+	case strings.HasPrefix(mt, "text/"), isJsonContentType(mt), mt == MimeTypeUrlEncodedForm,
+		strings.HasSuffix(mt, "/xml"), strings.HasSuffix(mt, "+xml"), strings.HasSuffix(mt, "/javascript"):
+		return true
+	case mt == "application/octet-stream":
+		// Unknown type, check if body is a valid UTF-8 text
+		return utf8.Valid(body)
+	}
+	return false
+}
+
+func isJsonContentType(contentType string) bool {
+	mt, _, _ := mime.ParseMediaType(contentType)
+	return mt == MimeTypeJson || strings.HasSuffix(mt, "+json")
+}
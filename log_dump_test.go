@@ -0,0 +1,175 @@
+package httpc_test
+
+import (
+	"context"
+	"github.com/nbs-go/httpc"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestLogDumpBody(t *testing.T) {
+	l, restore := newCaptureLogger()
+	defer restore()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/image":
+			w.Header().Set(httpc.HeaderContentType, "image/png")
+			_, _ = w.Write([]byte("\x89PNG\r\n\x1a\n\x00\x00\x00\rIHDR"))
+		case "/large":
+			_, _ = w.Write([]byte(strings.Repeat("a", 100)))
+		case "/multibyte":
+			w.Header().Set(httpc.HeaderContentType, "text/plain; charset=utf-8")
+			_, _ = w.Write([]byte("a" + strings.Repeat("é", 50)))
+		default:
+			w.Header().Set(httpc.HeaderContentType, httpc.MimeTypeJson)
+			_, _ = w.Write([]byte(`{"name":"john"}`))
+		}
+	}))
+	defer srv.Close()
+	rc := httpc.MustNewClient(srv.URL, httpc.LogDumpConfig(httpc.DumpConfig{
+		Level:       httpc.DumpBody,
+		MaxBodySize: 40,
+		PrettyJSON:  true,
+	}))
+
+	for _, p := range []string{"/image", "/large", "/multibyte", "/json"} {
+		_, body, err := rc.DoRequest(context.Background(), "GET", p)
+		if err != nil {
+			t.Errorf("unexpected error: %s", err)
+			return
+		}
+		if p == "/large" && len(body) != 100 {
+			t.Errorf("unexpected response body length: %d", len(body))
+		}
+	}
+	entries := l.Entries("HTTP Response Dump")
+	if len(entries) != 4 {
+		t.Errorf("unexpected dump count: %d", len(entries))
+		return
+	}
+	expected := []string{
+		"[Binary body omitted. Length = 16, Content-Type = image/png]",
+		strings.Repeat("a", 40) + "\n[Truncated 60 bytes]",
+		"\r\n\r\na" + strings.Repeat("é", 19) + "\n[Truncated 62 bytes]",
+		"{\n  \"name\": \"john\"\n}",
+	}
+	for i, s := range expected {
+		if !strings.Contains(entries[i].msg, s) {
+			t.Errorf("unexpected condition: %q is not found in dump.\n%s", s, entries[i].msg)
+		}
+	}
+}
+
+func TestLogDumpOnError(t *testing.T) {
+	l, restore := newCaptureLogger()
+	defer restore()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/fail" {
+			w.WriteHeader(http.StatusBadRequest)
+		}
+		_, _ = w.Write([]byte("body"))
+	}))
+	defer srv.Close()
+	rc := httpc.MustNewClient(srv.URL, httpc.LogDumpConfig(httpc.DumpConfig{Level: httpc.DumpBody, OnError: true}))
+
+	for _, p := range []string{"/ok", "/fail"} {
+		_, _, err := rc.DoRequest(context.Background(), "GET", p)
+		if err != nil {
+			t.Errorf("unexpected error: %s", err)
+			return
+		}
+	}
+	reqDump := l.Entries("HTTP Request Dump")
+	respDump := l.Entries("HTTP Response Dump")
+	if len(reqDump) != 1 || len(respDump) != 1 {
+		t.Errorf("unexpected dump count. Request = %d, Response = %d", len(reqDump), len(respDump))
+		return
+	}
+	if !strings.Contains(reqDump[0].msg, "GET /fail") || !strings.Contains(respDump[0].msg, "400 Bad Request") {
+		t.Errorf("unexpected dump.\n%s\n%s", reqDump[0].msg, respDump[0].msg)
+	}
+}
+
+func TestLogDumpPerRequest(t *testing.T) {
+	l, restore := newCaptureLogger()
+	defer restore()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("response-body"))
+	}))
+	defer srv.Close()
+	rc := httpc.MustNewClient(srv.URL, httpc.LogDump(true))
+
+	_, _, err := rc.DoRequest(context.Background(), "GET", "/disabled", httpc.DisableDump())
+	if err != nil {
+		t.Errorf("unexpected error: %s", err)
+		return
+	}
+	_, _, err = rc.DoRequest(context.Background(), "GET", "/headers", httpc.Dump(httpc.DumpConfig{Level: httpc.DumpHeaders}))
+	if err != nil {
+		t.Errorf("unexpected error: %s", err)
+		return
+	}
+	entries := l.Entries("HTTP Response Dump")
+	if len(entries) != 1 {
+		t.Errorf("unexpected dump count: %d", len(entries))
+		return
+	}
+	if strings.Contains(entries[0].msg, "response-body") {
+		t.Errorf("unexpected condition: body is dumped.\n%s", entries[0].msg)
+	}
+}
+
+func TestLogDumpJarCookies(t *testing.T) {
+	l, restore := newCaptureLogger()
+	defer restore()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.SetCookie(w, &http.Cookie{Name: "session", Value: "s3cret", Path: "/"})
+		w.WriteHeader(http.StatusBadRequest)
+	}))
+	defer srv.Close()
+	rc := httpc.MustNewClient(srv.URL, httpc.CookieJar(httpc.NewCookieJar()), httpc.LogAsCurl(),
+		httpc.LogDumpConfig(httpc.DumpConfig{Level: httpc.DumpHeaders, OnError: true}))
+
+	for i := 0; i < 2; i++ {
+		_, _, err := rc.DoRequest(context.Background(), "GET", "/")
+		if err != nil {
+			t.Errorf("unexpected error: %s", err)
+			return
+		}
+	}
+
+	// Assert cookies from jar are logged once, as sent by client
+	reqDump := l.Entries("HTTP Request Dump")
+	curl := l.Entries("HTTP Request as cURL")
+	if len(reqDump) != 2 || len(curl) != 2 {
+		t.Errorf("unexpected log count. Dump = %d, Curl = %d", len(reqDump), len(curl))
+		return
+	}
+	if strings.Contains(reqDump[0].msg, "Cookie:") || !strings.Contains(reqDump[1].msg, "Cookie: session=***\r\n") {
+		t.Errorf("unexpected dump.\n%s\n%s", reqDump[0].msg, reqDump[1].msg)
+	}
+	if strings.Contains(curl[0].msg, "Cookie:") || !strings.Contains(curl[1].msg, "-H 'Cookie: session=***'") {
+		t.Errorf("unexpected curl log.\n%s\n%s", curl[0].msg, curl[1].msg)
+	}
+}
+
+func TestLogDumpBodyReadError(t *testing.T) {
+	_, restore := newCaptureLogger()
+	defer restore()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// Close connection before the whole body is written
+		conn, buf, _ := w.(http.Hijacker).Hijack()
+		_, _ = buf.WriteString("HTTP/1.1 200 OK\r\nContent-Length: 100\r\n\r\npartial")
+		_ = buf.Flush()
+		_ = conn.Close()
+	}))
+	defer srv.Close()
+	rc := httpc.MustNewClient(srv.URL, httpc.LogDumpConfig(httpc.DumpConfig{Level: httpc.DumpBody}))
+
+	_, body, err := rc.DoRequest(context.Background(), "GET", "/")
+	if err == nil {
+		t.Errorf("expected error is not returned. Body = %s", body)
+	}
+}
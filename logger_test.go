@@ -0,0 +1,96 @@
+package httpc_test
+
+import (
+	"fmt"
+	"github.com/nbs-go/nlogger/v2"
+	logOption "github.com/nbs-go/nlogger/v2/option"
+	"strings"
+	"sync"
+)
+
+// captureLogger is a nlogger.Logger that captures log entries for assertion
+type captureLogger struct {
+	mu      *sync.Mutex
+	entries *[]logEntry
+}
+
+type logEntry struct {
+	level    string
+	msg      string
+	metadata map[string]interface{}
+}
+
+// newCaptureLogger register a capturing logger as global logger. Call restore to register previous logger
+func newCaptureLogger() (l *captureLogger, restore func()) {
+	prev := nlogger.Get()
+	l = &captureLogger{mu: new(sync.Mutex), entries: new([]logEntry)}
+	nlogger.Register(l)
+	return l, func() { nlogger.Register(prev) }
+}
+
+func (l *captureLogger) write(level string, msg string, options []logOption.SetterFunc) {
+	o := logOption.Evaluate(options)
+	if len(o.FmtArgs) > 0 {
+		msg = fmt.Sprintf(msg, o.FmtArgs...)
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	*l.entries = append(*l.entries, logEntry{level: level, msg: msg, metadata: o.Metadata})
+}
+
+// Entries returns captured entries which message contains s
+func (l *captureLogger) Entries(s string) []logEntry {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	var result []logEntry
+	for _, e := range *l.entries {
+		if strings.Contains(e.msg, s) {
+			result = append(result, e)
+		}
+	}
+	return result
+}
+
+func (l *captureLogger) Fatal(msg string, options ...logOption.SetterFunc) {
+	l.write("FATAL", msg, options)
+}
+
+func (l *captureLogger) Fatalf(format string, args ...interface{}) {
+	l.write("FATAL", fmt.Sprintf(format, args...), nil)
+}
+
+func (l *captureLogger) Error(msg string, options ...logOption.SetterFunc) {
+	l.write("ERROR", msg, options)
+}
+
+func (l *captureLogger) Errorf(format string, args ...interface{}) {
+	l.write("ERROR", fmt.Sprintf(format, args...), nil)
+}
+
+func (l *captureLogger) Warn(msg string, options ...logOption.SetterFunc) {
+	l.write("WARN", msg, options)
+}
+
+func (l *captureLogger) Warnf(format string, args ...interface{}) {
+	l.write("WARN", fmt.Sprintf(format, args...), nil)
+}
+
+func (l *captureLogger) Info(msg string, options ...logOption.SetterFunc) {
+	l.write("INFO", msg, options)
+}
+
+func (l *captureLogger) Infof(format string, args ...interface{}) {
+	l.write("INFO", fmt.Sprintf(format, args...), nil)
+}
+
+func (l *captureLogger) Debug(msg string, options ...logOption.SetterFunc) {
+	l.write("DEBUG", msg, options)
+}
+
+func (l *captureLogger) Debugf(format string, args ...interface{}) {
+	l.write("DEBUG", fmt.Sprintf(format, args...), nil)
+}
+
+func (l *captureLogger) NewChild(_ ...logOption.SetterFunc) nlogger.Logger {
+	return l
+}
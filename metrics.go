@@ -0,0 +1,38 @@
+package httpc
+
+import (
+	"strconv"
+	"time"
+)
+
+// StatusClassError is the status class label of request that failed to get a response
+const StatusClassError = "error"
+
+// MetricLabels is labels of request metrics
+type MetricLabels struct {
+	// Namespace is the Client namespace
+	Namespace string
+	Method    string
+	// Endpoint is the endpoint path template, before path parameters are resolved
+	Endpoint string
+	Host     string
+	// StatusClass is the response status class, e.g. "2xx", or StatusClassError if request failed. It is empty in
+	// RequestStarted
+	StatusClass string
+}
+
+// Metrics collects request metrics. Implementation must be safe for concurrent use
+type Metrics interface {
+	// RequestStarted is called before request is sent. It could be used to track in-flight requests
+	RequestStarted(l MetricLabels)
+	// RequestFinished is called after response body is read or request failed
+	RequestFinished(l MetricLabels, duration time.Duration, requestSize int, responseSize int)
+}
+
+// statusClass returns status class label of status code
+func statusClass(statusCode int) string {
+	if statusCode <= 0 {
+		return StatusClassError
+	}
+	return strconv.Itoa(statusCode/100) + "xx"
+}
@@ -0,0 +1,48 @@
+package oauth2
+
+import (
+	"context"
+	"github.com/nbs-go/httpc"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// ClientCredentialsConfig is configuration for OAuth2 client_credentials grant as defined in RFC 6749 section 4.4
+type ClientCredentialsConfig struct {
+	Endpoint     Endpoint
+	ClientId     string
+	ClientSecret string
+	Scopes       []string
+	// EndpointParams is additional parameters sent to token endpoint, e.g. audience
+	EndpointParams url.Values
+	// ExpiryDelta is the duration before token expiry when token is refreshed. Default value is DefaultExpiryDelta
+	ExpiryDelta time.Duration
+}
+
+// TokenSource returns cached TokenSource that retrieve token using client_credentials grant
+func (c *ClientCredentialsConfig) TokenSource() *httpc.CachedTokenSource {
+	return newTokenSource(c.token, c.ExpiryDelta)
+}
+
+// AuthProvider returns httpc.AuthProvider that authenticate request using client_credentials grant token
+func (c *ClientCredentialsConfig) AuthProvider() httpc.AuthProvider {
+	return httpc.NewTokenAuth(c.TokenSource())
+}
+
+func (c *ClientCredentialsConfig) token(ctx context.Context) (*httpc.Token, error) {
+	params := url.Values{
+		"grant_type": {GrantTypeClientCredentials},
+	}
+	if len(c.Scopes) > 0 {
+		params.Set("scope", strings.Join(c.Scopes, " "))
+	}
+	for k, v := range c.EndpointParams {
+		params[k] = v
+	}
+	r, err := retrieveToken(ctx, &c.Endpoint, c.ClientId, c.ClientSecret, params)
+	if err != nil {
+		return nil, err
+	}
+	return r.token()
+}
@@ -0,0 +1,181 @@
+package oauth2
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/asn1"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"github.com/google/uuid"
+	"github.com/nbs-go/httpc"
+	"math/big"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// JWTConfig is configuration for OAuth2 JWT bearer grant as defined in RFC 7523 section 2.1
+type JWTConfig struct {
+	Endpoint Endpoint
+	// ClientId and ClientSecret is optional client authentication to token endpoint
+	ClientId     string
+	ClientSecret string
+	// Issuer is the iss claim of assertion
+	Issuer string
+	// Subject is the sub claim of assertion. If empty, Issuer is used
+	Subject string
+	// Audience is the aud claim of assertion, usually the token endpoint url
+	Audience string
+	Scopes   []string
+	// PrivateKey signs assertion. Supported keys are *rsa.PrivateKey (RS256) and *ecdsa.PrivateKey with P-256 curve (ES256)
+	PrivateKey crypto.Signer
+	// KeyId is the kid header of assertion
+	KeyId string
+	// Lifetime is assertion lifetime. Default value is 1 hour
+	Lifetime time.Duration
+	// PrivateClaims is additional claims set in assertion
+	PrivateClaims map[string]interface{}
+	// ExpiryDelta is the duration before token expiry when token is refreshed. Default value is DefaultExpiryDelta
+	ExpiryDelta time.Duration
+}
+
+// TokenSource returns cached TokenSource that retrieve token using JWT bearer grant
+func (c *JWTConfig) TokenSource() *httpc.CachedTokenSource {
+	return newTokenSource(c.token, c.ExpiryDelta)
+}
+
+// AuthProvider returns httpc.AuthProvider that authenticate request using JWT bearer grant token
+func (c *JWTConfig) AuthProvider() httpc.AuthProvider {
+	return httpc.NewTokenAuth(c.TokenSource())
+}
+
+func (c *JWTConfig) token(ctx context.Context) (*httpc.Token, error) {
+	assertion, err := c.assertion()
+	if err != nil {
+		return nil, err
+	}
+	params := url.Values{
+		"grant_type": {GrantTypeJWTBearer},
+		"assertion":  {assertion},
+	}
+	if len(c.Scopes) > 0 {
+		params.Set("scope", strings.Join(c.Scopes, " "))
+	}
+	r, err := retrieveToken(ctx, &c.Endpoint, c.ClientId, c.ClientSecret, params)
+	if err != nil {
+		return nil, err
+	}
+	return r.token()
+}
+
+// assertion creates signed JWT assertion
+func (c *JWTConfig) assertion() (string, error) {
+	var alg string
+	switch k := c.PrivateKey.(type) {
+	case *rsa.PrivateKey:
+		alg = "RS256"
+	case *ecdsa.PrivateKey:
+		if k.Curve.Params().BitSize != 256 {
+			return "", fmt.Errorf("oauth2: Unsupported ECDSA curve. Curve = %s", k.Curve.Params().Name)
+		}
+		alg = "ES256"
+	default:
+		return "", fmt.Errorf("oauth2: Unsupported JWT private key type. Type = %T", c.PrivateKey)
+	}
+	// Compose header
+	header := map[string]interface{}{
+		"alg": alg,
+		"typ": "JWT",
+	}
+	if c.KeyId != "" {
+		header["kid"] = c.KeyId
+	}
+	// Compose claims
+	lifetime := c.Lifetime
+	if lifetime <= 0 {
+		lifetime = time.Hour
+	}
+	now := time.Now()
+	claims := make(map[string]interface{}, len(c.PrivateClaims)+6)
+	for k, v := range c.PrivateClaims {
+		claims[k] = v
+	}
+	sub := c.Subject
+	if sub == "" {
+		sub = c.Issuer
+	}
+	claims["iss"] = c.Issuer
+	claims["sub"] = sub
+	claims["aud"] = c.Audience
+	claims["iat"] = now.Unix()
+	claims["exp"] = now.Add(lifetime).Unix()
+	claims["jti"] = uuid.New().String()
+	// Encode
+	h, err := json.Marshal(header)
+	if err != nil {
+		return "", err
+	}
+	cl, err := json.Marshal(claims)
+	if err != nil {
+		return "", fmt.Errorf("oauth2: Failed to encode JWT claims. Error = %w", err)
+	}
+	signingInput := base64.RawURLEncoding.EncodeToString(h) + "." + base64.RawURLEncoding.EncodeToString(cl)
+	sig, err := signJWT(c.PrivateKey, signingInput)
+	if err != nil {
+		return "", err
+	}
+	return signingInput + "." + base64.RawURLEncoding.EncodeToString(sig), nil
+}
+
+func signJWT(key crypto.Signer, signingInput string) ([]byte, error) {
+	digest := sha256.Sum256([]byte(signingInput))
+	sig, err := key.Sign(rand.Reader, digest[:], crypto.SHA256)
+	if err != nil {
+		return nil, fmt.Errorf("oauth2: Failed to sign JWT. Error = %w", err)
+	}
+	if _, ok := key.(*ecdsa.PrivateKey); !ok {
+		return sig, nil
+	}
+	// Convert ASN.1 signature to JWS R || S format as defined in RFC 7518 section 3.4
+	var esig struct {
+		R, S *big.Int
+	}
+	if _, err = asn1.Unmarshal(sig, &esig); err != nil {
+		return nil, fmt.Errorf("oauth2: Failed to parse ECDSA signature. Error = %w", err)
+	}
+	out := make([]byte, 64)
+	esig.R.FillBytes(out[:32])
+	esig.S.FillBytes(out[32:])
+	return out, nil
+}
+
+// ParsePrivateKeyPEM parse PEM encoded PKCS#1, PKCS#8 or SEC 1 private key
+func ParsePrivateKeyPEM(b []byte) (crypto.Signer, error) {
+	block, _ := pem.Decode(b)
+	if block == nil {
+		return nil, errors.New("oauth2: Invalid private key, PEM block not found")
+	}
+	if k, err := x509.ParsePKCS1PrivateKey(block.Bytes); err == nil {
+		return k, nil
+	}
+	if k, err := x509.ParseECPrivateKey(block.Bytes); err == nil {
+		return k, nil
+	}
+	k, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("oauth2: Failed to parse private key. Error = %w", err)
+	}
+	s, ok := k.(crypto.Signer)
+	if !ok {
+		return nil, fmt.Errorf("oauth2: Unsupported private key type. Type = %T", k)
+	}
+	return s, nil
+}
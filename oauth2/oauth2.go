@@ -0,0 +1,134 @@
+// Package oauth2 implements OAuth2 token grants on top of httpc.Client. Token sources are cached and safe for
+// concurrent use, and could be plugged into httpc.Client as an auth provider
+package oauth2
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/nbs-go/httpc"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// DefaultExpiryDelta is the duration before token expiry when token is refreshed
+const DefaultExpiryDelta = 10 * time.Second
+
+// Grant types
+const (
+	GrantTypeClientCredentials = "client_credentials"
+	GrantTypeRefreshToken      = "refresh_token"
+	GrantTypeJWTBearer         = "urn:ietf:params:oauth:grant-type:jwt-bearer"
+)
+
+// AuthStyle defines how client credentials are sent to token endpoint
+type AuthStyle int8
+
+const (
+	// AuthStyleHeader sends client credentials in HTTP Basic authentication header
+	AuthStyleHeader AuthStyle = iota
+	// AuthStyleParams sends client credentials as client_id and client_secret form parameters
+	AuthStyleParams
+)
+
+// Endpoint is OAuth2 authorization server token endpoint
+type Endpoint struct {
+	// Client is httpc.Client with authorization server base url
+	Client *httpc.Client
+	// TokenPath is the token endpoint path relative to Client base url
+	TokenPath string
+	// AuthStyle defines how client credentials are sent. Default value is AuthStyleHeader
+	AuthStyle AuthStyle
+}
+
+// RetrieveError is returned when token endpoint responds with non-2xx status
+type RetrieveError struct {
+	Response         *http.Response
+	Body             []byte
+	ErrorCode        string `json:"error"`
+	ErrorDescription string `json:"error_description"`
+}
+
+func (e *RetrieveError) Error() string {
+	if e.ErrorCode != "" {
+		return fmt.Sprintf("oauth2: Failed to retrieve token. Status = %s, Error = %s, Description = %s",
+			e.Response.Status, e.ErrorCode, e.ErrorDescription)
+	}
+	return fmt.Sprintf("oauth2: Failed to retrieve token. Status = %s, Body = %s", e.Response.Status, e.Body)
+}
+
+// tokenResponse is token endpoint success response as defined in RFC 6749 section 5.1
+type tokenResponse struct {
+	AccessToken  string      `json:"access_token"`
+	TokenType    string      `json:"token_type"`
+	RefreshToken string      `json:"refresh_token"`
+	ExpiresIn    json.Number `json:"expires_in"`
+}
+
+func (r *tokenResponse) token() (*httpc.Token, error) {
+	t := httpc.Token{
+		AccessToken: r.AccessToken,
+		TokenType:   r.TokenType,
+	}
+	// Normalize bearer token type, some servers respond with lower case
+	if strings.EqualFold(t.TokenType, httpc.TokenTypeBearer) {
+		t.TokenType = httpc.TokenTypeBearer
+	}
+	if r.ExpiresIn != "" {
+		sec, err := r.ExpiresIn.Int64()
+		if err != nil {
+			return nil, fmt.Errorf("oauth2: Invalid expires_in value. Value = %s", r.ExpiresIn)
+		}
+		if sec > 0 {
+			t.Expiry = time.Now().Add(time.Duration(sec) * time.Second)
+		}
+	}
+	return &t, nil
+}
+
+// retrieveToken request token to token endpoint
+func retrieveToken(ctx context.Context, e *Endpoint, clientId string, clientSecret string, params url.Values) (*tokenResponse, error) {
+	args := make([]httpc.SetRequestOptionFn, 0, 3)
+	if clientId != "" {
+		if e.AuthStyle == AuthStyleParams {
+			params.Set("client_id", clientId)
+			if clientSecret != "" {
+				params.Set("client_secret", clientSecret)
+			}
+		} else {
+			args = append(args, httpc.BasicAuth(url.QueryEscape(clientId), url.QueryEscape(clientSecret)))
+		}
+	}
+	args = append(args,
+		httpc.SetUrlEncodedFormBody(params),
+		httpc.SetHeader("Accept", httpc.MimeTypeJson),
+	)
+	resp, body, err := e.Client.DoRequest(ctx, httpc.MethodPost, e.TokenPath, args...)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		rErr := RetrieveError{Response: resp, Body: body}
+		_ = json.Unmarshal(body, &rErr)
+		return nil, &rErr
+	}
+	var r tokenResponse
+	err = json.Unmarshal(body, &r)
+	if err != nil {
+		return nil, fmt.Errorf("oauth2: Failed to parse token response. Error = %w", err)
+	}
+	if r.AccessToken == "" {
+		return nil, fmt.Errorf("oauth2: Server response missing access_token")
+	}
+	return &r, nil
+}
+
+// newTokenSource wraps token retrieval function with cache
+func newTokenSource(fn httpc.TokenSourceFn, expiryDelta time.Duration) *httpc.CachedTokenSource {
+	if expiryDelta <= 0 {
+		expiryDelta = DefaultExpiryDelta
+	}
+	return httpc.NewCachedTokenSource(fn, expiryDelta)
+}
@@ -0,0 +1,204 @@
+package oauth2_test
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"github.com/nbs-go/httpc"
+	"github.com/nbs-go/httpc/oauth2"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+)
+
+type authServer struct {
+	*httptest.Server
+	issued int32
+	grants []string
+	mu     sync.Mutex
+	verify func(assertion string) bool
+}
+
+func newAuthServer() *authServer {
+	s := authServer{}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/token", func(w http.ResponseWriter, r *http.Request) {
+		_ = r.ParseForm()
+		s.mu.Lock()
+		s.grants = append(s.grants, r.PostForm.Get("grant_type"))
+		s.mu.Unlock()
+		w.Header().Set(httpc.HeaderContentType, httpc.MimeTypeJson)
+		switch r.PostForm.Get("grant_type") {
+		case oauth2.GrantTypeClientCredentials:
+			if u, p, _ := r.BasicAuth(); u != "client" || p != "secret" {
+				w.WriteHeader(http.StatusUnauthorized)
+				_, _ = w.Write([]byte(`{"error":"invalid_client","error_description":"bad credentials"}`))
+				return
+			}
+		case oauth2.GrantTypeRefreshToken:
+			if r.PostForm.Get("client_id") != "client" || !strings.HasPrefix(r.PostForm.Get("refresh_token"), "rt-") {
+				w.WriteHeader(http.StatusBadRequest)
+				_, _ = w.Write([]byte(`{"error":"invalid_grant"}`))
+				return
+			}
+		case oauth2.GrantTypeJWTBearer:
+			if !s.verify(r.PostForm.Get("assertion")) {
+				w.WriteHeader(http.StatusBadRequest)
+				_, _ = w.Write([]byte(`{"error":"invalid_grant"}`))
+				return
+			}
+		}
+		n := atomic.AddInt32(&s.issued, 1)
+		_, _ = fmt.Fprintf(w, `{"access_token":"at-%d","token_type":"bearer","expires_in":3600,"refresh_token":"rt-%d"}`, n, n)
+	})
+	mux.HandleFunc("/resource", func(w http.ResponseWriter, r *http.Request) {
+		if !strings.HasPrefix(r.Header.Get(httpc.HeaderAuthorization), "Bearer at-") {
+			w.WriteHeader(http.StatusUnauthorized)
+		}
+	})
+	s.Server = httptest.NewServer(mux)
+	return &s
+}
+
+func TestClientCredentials(t *testing.T) {
+	srv := newAuthServer()
+	defer srv.Close()
+	hc := httpc.MustNewClient(srv.URL)
+	cfg := oauth2.ClientCredentialsConfig{
+		Endpoint:     oauth2.Endpoint{Client: hc, TokenPath: "/token"},
+		ClientId:     "client",
+		ClientSecret: "secret",
+		Scopes:       []string{"read", "write"},
+	}
+	rc := httpc.MustNewClient(srv.URL, httpc.ClientAuth(cfg.AuthProvider()))
+
+	// Assert token is shared across goroutines
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			resp, _, err := rc.DoRequest(context.Background(), "GET", "/resource")
+			if err != nil {
+				t.Errorf("unexpected error: %s", err)
+				return
+			}
+			if resp.StatusCode != http.StatusOK {
+				t.Errorf("unexpected response status code. StatusCode = %d", resp.StatusCode)
+			}
+		}()
+	}
+	wg.Wait()
+	if n := atomic.LoadInt32(&srv.issued); n != 1 {
+		t.Errorf("unexpected issued token count: %d", n)
+	}
+}
+
+func TestClientCredentialsError(t *testing.T) {
+	srv := newAuthServer()
+	defer srv.Close()
+	cfg := oauth2.ClientCredentialsConfig{
+		Endpoint:     oauth2.Endpoint{Client: httpc.MustNewClient(srv.URL), TokenPath: "/token"},
+		ClientId:     "client",
+		ClientSecret: "invalid",
+	}
+	_, err := cfg.TokenSource().Token(context.Background())
+	rErr, ok := err.(*oauth2.RetrieveError)
+	if !ok {
+		t.Errorf("unexpected error: %v", err)
+		return
+	}
+	if rErr.ErrorCode != "invalid_client" {
+		t.Errorf("unexpected error code: %s", rErr.ErrorCode)
+	}
+}
+
+func TestRefreshToken(t *testing.T) {
+	srv := newAuthServer()
+	defer srv.Close()
+	var rotated string
+	cfg := oauth2.RefreshTokenConfig{
+		Endpoint:     oauth2.Endpoint{Client: httpc.MustNewClient(srv.URL), TokenPath: "/token", AuthStyle: oauth2.AuthStyleParams},
+		ClientId:     "client",
+		RefreshToken: "rt-0",
+		OnRefreshTokenRotated: func(refreshToken string) {
+			rotated = refreshToken
+		},
+	}
+	ts := cfg.TokenSource()
+	tk, err := ts.Token(context.Background())
+	if err != nil {
+		t.Errorf("unexpected error: %s", err)
+		return
+	}
+	if tk.HeaderValue() != "Bearer at-1" {
+		t.Errorf("unexpected token: %s", tk.HeaderValue())
+	}
+	if rotated != "rt-1" {
+		t.Errorf("unexpected rotated refresh token: %s", rotated)
+	}
+	// Assert new refresh token is used
+	ts.Invalidate()
+	if _, err = ts.Token(context.Background()); err != nil {
+		t.Errorf("unexpected error: %s", err)
+		return
+	}
+	if rotated != "rt-2" {
+		t.Errorf("unexpected rotated refresh token: %s", rotated)
+	}
+}
+
+func TestJWTBearer(t *testing.T) {
+	rsaKey, _ := rsa.GenerateKey(rand.Reader, 2048)
+	ecKey, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+
+	for _, key := range []crypto.Signer{rsaKey, ecKey} {
+		srv := newAuthServer()
+		var claims map[string]interface{}
+		srv.verify = func(assertion string) bool {
+			parts := strings.Split(assertion, ".")
+			if len(parts) != 3 {
+				return false
+			}
+			digest := sha256.Sum256([]byte(parts[0] + "." + parts[1]))
+			sig, _ := base64.RawURLEncoding.DecodeString(parts[2])
+			cb, _ := base64.RawURLEncoding.DecodeString(parts[1])
+			_ = json.Unmarshal(cb, &claims)
+			switch k := key.(type) {
+			case *rsa.PrivateKey:
+				return rsa.VerifyPKCS1v15(&k.PublicKey, crypto.SHA256, digest[:], sig) == nil
+			case *ecdsa.PrivateKey:
+				r, s := new(big.Int).SetBytes(sig[:32]), new(big.Int).SetBytes(sig[32:])
+				return ecdsa.Verify(&k.PublicKey, digest[:], r, s)
+			}
+			return false
+		}
+		cfg := oauth2.JWTConfig{
+			Endpoint:      oauth2.Endpoint{Client: httpc.MustNewClient(srv.URL), TokenPath: "/token"},
+			Issuer:        "service@nbs.dev",
+			Audience:      srv.URL + "/token",
+			PrivateKey:    key,
+			PrivateClaims: map[string]interface{}{"tenant": "nbs"},
+		}
+		_, err := cfg.TokenSource().Token(context.Background())
+		srv.Close()
+		if err != nil {
+			t.Errorf("unexpected error: %s. KeyType = %T", err, key)
+			continue
+		}
+		if claims["sub"] != "service@nbs.dev" || claims["tenant"] != "nbs" {
+			t.Errorf("unexpected claims: %v", claims)
+		}
+	}
+}
@@ -0,0 +1,72 @@
+package oauth2
+
+import (
+	"context"
+	"errors"
+	"github.com/nbs-go/httpc"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+// RefreshTokenConfig is configuration for OAuth2 refresh_token grant as defined in RFC 6749 section 6
+type RefreshTokenConfig struct {
+	Endpoint     Endpoint
+	ClientId     string
+	ClientSecret string
+	// RefreshToken is the initial refresh token
+	RefreshToken string
+	Scopes       []string
+	// OnRefreshTokenRotated is called when server issues a new refresh token, so it could be persisted
+	OnRefreshTokenRotated func(refreshToken string)
+	// ExpiryDelta is the duration before token expiry when token is refreshed. Default value is DefaultExpiryDelta
+	ExpiryDelta time.Duration
+}
+
+// TokenSource returns cached TokenSource that retrieve token using refresh_token grant
+func (c *RefreshTokenConfig) TokenSource() *httpc.CachedTokenSource {
+	s := refreshTokenSource{
+		config:       c,
+		refreshToken: c.RefreshToken,
+	}
+	return newTokenSource(s.token, c.ExpiryDelta)
+}
+
+// AuthProvider returns httpc.AuthProvider that authenticate request using refresh_token grant token
+func (c *RefreshTokenConfig) AuthProvider() httpc.AuthProvider {
+	return httpc.NewTokenAuth(c.TokenSource())
+}
+
+type refreshTokenSource struct {
+	config       *RefreshTokenConfig
+	mu           sync.Mutex
+	refreshToken string
+}
+
+func (s *refreshTokenSource) token(ctx context.Context) (*httpc.Token, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.refreshToken == "" {
+		return nil, errors.New("oauth2: Refresh token is not set")
+	}
+	params := url.Values{
+		"grant_type":    {GrantTypeRefreshToken},
+		"refresh_token": {s.refreshToken},
+	}
+	if len(s.config.Scopes) > 0 {
+		params.Set("scope", strings.Join(s.config.Scopes, " "))
+	}
+	r, err := retrieveToken(ctx, &s.config.Endpoint, s.config.ClientId, s.config.ClientSecret, params)
+	if err != nil {
+		return nil, err
+	}
+	// Keep rotated refresh token
+	if r.RefreshToken != "" && r.RefreshToken != s.refreshToken {
+		s.refreshToken = r.RefreshToken
+		if s.config.OnRefreshTokenRotated != nil {
+			s.config.OnRefreshTokenRotated(r.RefreshToken)
+		}
+	}
+	return r.token()
+}
@@ -0,0 +1,22 @@
+module github.com/nbs-go/httpc/otel
+
+go 1.17
+
+// Use local httpc until a version with Tracer API is tagged
+replace github.com/nbs-go/httpc => ../
+
+require (
+	github.com/nbs-go/httpc v0.7.0
+	go.opentelemetry.io/otel v1.10.0
+	go.opentelemetry.io/otel/sdk v1.10.0
+	go.opentelemetry.io/otel/trace v1.10.0
+)
+
+require (
+	github.com/go-logr/logr v1.2.3 // indirect
+	github.com/go-logr/stdr v1.2.2 // indirect
+	github.com/google/uuid v1.3.0 // indirect
+	github.com/nbs-go/nlogger/v2 v2.2.2 // indirect
+	golang.org/x/net v0.17.0 // indirect
+	golang.org/x/sys v0.13.0 // indirect
+)
@@ -0,0 +1,111 @@
+// Package otel implements httpc.Tracer using OpenTelemetry
+package otel
+
+import (
+	"context"
+	"fmt"
+	"github.com/nbs-go/httpc"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// InstrumentationName is the name of tracer
+const InstrumentationName = "github.com/nbs-go/httpc"
+
+type SetOptionFn func(o *options)
+
+type options struct {
+	provider trace.TracerProvider
+}
+
+// TracerProvider set provider to create tracer. By default, global provider is used
+func TracerProvider(p trace.TracerProvider) SetOptionFn {
+	return func(o *options) {
+		o.provider = p
+	}
+}
+
+// NewTracer creates httpc.Tracer that starts OpenTelemetry spans
+func NewTracer(args ...SetOptionFn) httpc.Tracer {
+	o := options{
+		provider: otel.GetTracerProvider(),
+	}
+	for _, fn := range args {
+		fn(&o)
+	}
+	return &tracer{
+		tracer: o.provider.Tracer(InstrumentationName),
+	}
+}
+
+type tracer struct {
+	tracer trace.Tracer
+}
+
+func (t *tracer) Start(ctx context.Context, name string, kind httpc.SpanKind, attrs ...httpc.Attribute) (context.Context, httpc.Span) {
+	sk := trace.SpanKindInternal
+	if kind == httpc.SpanKindClient {
+		sk = trace.SpanKindClient
+	}
+	ctx, s := t.tracer.Start(ctx, name, trace.WithSpanKind(sk), trace.WithAttributes(convertAttributes(attrs)...))
+	return ctx, &span{span: s}
+}
+
+type span struct {
+	span trace.Span
+}
+
+func (s *span) SetAttributes(attrs ...httpc.Attribute) {
+	s.span.SetAttributes(convertAttributes(attrs)...)
+	// Span is failed if error type is set, e.g. on 4xx or 5xx response status
+	for _, a := range attrs {
+		if a.Key == httpc.AttrErrorType {
+			s.span.SetStatus(codes.Error, fmt.Sprint(a.Value))
+		}
+	}
+}
+
+func (s *span) RecordError(err error) {
+	s.span.RecordError(err)
+	s.span.SetStatus(codes.Error, err.Error())
+}
+
+func (s *span) SpanContext() httpc.SpanContext {
+	sc := s.span.SpanContext()
+	return httpc.SpanContext{
+		TraceId:    sc.TraceID(),
+		SpanId:     sc.SpanID(),
+		Sampled:    sc.IsSampled(),
+		TraceState: sc.TraceState().String(),
+	}
+}
+
+func (s *span) End() {
+	s.span.End()
+}
+
+func convertAttributes(attrs []httpc.Attribute) []attribute.KeyValue {
+	result := make([]attribute.KeyValue, len(attrs))
+	for i, a := range attrs {
+		k := attribute.Key(a.Key)
+		switch v := a.Value.(type) {
+		case string:
+			result[i] = k.String(v)
+		case int:
+			result[i] = k.Int(v)
+		case int64:
+			result[i] = k.Int64(v)
+		case float64:
+			result[i] = k.Float64(v)
+		case bool:
+			result[i] = k.Bool(v)
+		case []string:
+			result[i] = k.StringSlice(v)
+		default:
+			result[i] = k.String(fmt.Sprint(v))
+		}
+	}
+	return result
+}
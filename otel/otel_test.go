@@ -0,0 +1,59 @@
+package otel_test
+
+import (
+	"context"
+	"github.com/nbs-go/httpc"
+	httpcOtel "github.com/nbs-go/httpc/otel"
+	"go.opentelemetry.io/otel/codes"
+	sdkTrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestTracer(t *testing.T) {
+	var traceParent string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		traceParent = r.Header.Get(httpc.HeaderTraceParent)
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer srv.Close()
+	recorder := tracetest.NewSpanRecorder()
+	tp := sdkTrace.NewTracerProvider(sdkTrace.WithSpanProcessor(recorder))
+	rc := httpc.MustNewClient(srv.URL, httpc.Tracing(httpcOtel.NewTracer(httpcOtel.TracerProvider(tp))))
+
+	_, _, err := rc.DoRequest(context.Background(), "GET", "/items")
+	if err != nil {
+		t.Errorf("unexpected error: %s", err)
+		return
+	}
+	spans := recorder.Ended()
+	if len(spans) != 2 {
+		t.Errorf("unexpected span count: %d", len(spans))
+		return
+	}
+	// Attempt span ends before request span
+	attempt, req := spans[0], spans[1]
+	if attempt.SpanKind() != trace.SpanKindClient || req.SpanKind() != trace.SpanKindInternal ||
+		attempt.Parent().SpanID() != req.SpanContext().SpanID() {
+		t.Errorf("unexpected spans. Attempt = %v, Request = %v", attempt.SpanKind(), req.SpanKind())
+	}
+	expected := "00-" + attempt.SpanContext().TraceID().String() + "-" + attempt.SpanContext().SpanID().String() + "-01"
+	if traceParent != expected {
+		t.Errorf("unexpected traceparent. Expected = %s, Actual = %s", expected, traceParent)
+	}
+	found := false
+	for _, a := range req.Attributes() {
+		if string(a.Key) == httpc.AttrHTTPResponseStatus && a.Value.AsInt64() == http.StatusBadGateway {
+			found = true
+		}
+	}
+	if !found {
+		t.Errorf("unexpected condition: status code attribute is not found. %v", req.Attributes())
+	}
+	if req.Status().Code != codes.Error || req.Status().Description != "502" {
+		t.Errorf("unexpected span status: %v", req.Status())
+	}
+}
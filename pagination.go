@@ -0,0 +1,439 @@
+package httpc
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// Page contains a fetched page of a paginated endpoint
+type Page struct {
+	// Number is the sequence of fetched page, starts from 1
+	Number int
+	// Response is the http response of page request
+	Response *http.Response
+	// Body is the raw response body
+	Body []byte
+	// Items is the raw JSON array of page items
+	Items json.RawMessage
+	// ItemCount is the count of items in page
+	ItemCount int
+}
+
+// PageRequest describes request parameters to fetch a page
+type PageRequest struct {
+	// EndpointPath override endpoint path for the page request. If empty, initial endpoint path will be used
+	EndpointPath string
+	// Query set query parameters for the page request, values will replace existing values with the same key
+	Query url.Values
+	// ResetQuery discard query parameters that is set in initial request options
+	ResetQuery bool
+}
+
+// PageStrategy defines how to request pages of a paginated endpoint
+type PageStrategy interface {
+	// FirstPage returns request parameters for the first page
+	FirstPage() *PageRequest
+	// NextPage inspects the fetched page and returns request parameters for the next page. Return nil to stop iteration
+	NextPage(p *Page) (*PageRequest, error)
+}
+
+// NewPaginator creates a paginator to iterate pages of an endpoint using the given strategy
+func NewPaginator(c *Client, method Method, endpointPath string, strategy PageStrategy, args ...SetRequestOptionFn) *Paginator {
+	if len(args) == 0 {
+		args = make([]SetRequestOptionFn, 0)
+	}
+	return &Paginator{
+		client:       c,
+		method:       method,
+		endpointPath: endpointPath,
+		strategy:     strategy,
+		args:         args,
+	}
+}
+
+type Paginator struct {
+	client       *Client
+	method       Method
+	endpointPath string
+	strategy     PageStrategy
+	args         []SetRequestOptionFn
+	itemsField   string
+	maxPages     int
+	prefetch     int
+}
+
+// ItemsField set dot-separated path to items array in JSON response body, e.g. "data" or "result.items".
+// If not set, response body is expected to be a JSON array
+func (p *Paginator) ItemsField(path string) *Paginator {
+	p.itemsField = path
+	return p
+}
+
+// MaxPages set maximum number of pages to be fetched. Zero value means no limit
+func (p *Paginator) MaxPages(n int) *Paginator {
+	p.maxPages = n
+	return p
+}
+
+// Prefetch set number of pages to be fetched in the background ahead of the consumer.
+// Zero value means pages are only fetched when requested
+func (p *Paginator) Prefetch(n int) *Paginator {
+	p.prefetch = n
+	return p
+}
+
+// Iterate starts page iteration. Iterator must be closed after used to release prefetching resources
+func (p *Paginator) Iterate(ctx context.Context) *PageIterator {
+	ctx, cancel := context.WithCancel(ctx)
+	it := &PageIterator{
+		paginator: p,
+		ctx:       ctx,
+		cancel:    cancel,
+		next:      p.strategy.FirstPage(),
+	}
+	if p.prefetch > 0 {
+		it.pages = make(chan pageResult, p.prefetch)
+		go it.prefetchPages()
+	}
+	return it
+}
+
+// All fetch all pages and append items to dst. dst must be a pointer to slice
+func (p *Paginator) All(ctx context.Context, dst interface{}) error {
+	rv := reflect.ValueOf(dst)
+	if rv.Kind() != reflect.Ptr || rv.Elem().Kind() != reflect.Slice {
+		return fmt.Errorf("httpc: Paginator.All() dst must be a pointer to slice. Type = %T", dst)
+	}
+	sv := rv.Elem()
+	it := p.Iterate(ctx)
+	defer it.Close()
+	for it.Next() {
+		items := reflect.New(sv.Type())
+		err := it.Scan(items.Interface())
+		if err != nil {
+			return err
+		}
+		sv.Set(reflect.AppendSlice(sv, items.Elem()))
+	}
+	return it.Err()
+}
+
+type pageResult struct {
+	page *Page
+	err  error
+}
+
+// PageIterator fetch pages lazily from a Paginator
+type PageIterator struct {
+	paginator *Paginator
+	ctx       context.Context
+	cancel    context.CancelFunc
+	next      *PageRequest
+	count     int
+	pages     chan pageResult
+	current   *Page
+	err       error
+}
+
+// Next advances iterator to the next page. It returns false when there are no more pages or an error occurred
+func (it *PageIterator) Next() bool {
+	if it.err != nil {
+		return false
+	}
+	var r pageResult
+	if it.pages != nil {
+		var ok bool
+		r, ok = <-it.pages
+		if !ok {
+			it.current = nil
+			return false
+		}
+	} else {
+		r.page, r.err = it.fetch()
+	}
+	if r.err != nil {
+		it.err = r.err
+		it.current = nil
+		return false
+	}
+	it.current = r.page
+	return r.page != nil
+}
+
+// Page returns current page
+func (it *PageIterator) Page() *Page {
+	return it.current
+}
+
+// Scan decodes current page items into dst. dst must be a pointer to slice
+func (it *PageIterator) Scan(dst interface{}) error {
+	if it.current == nil {
+		return errors.New("httpc: no page to scan, Next() must be called first")
+	}
+	return json.Unmarshal(it.current.Items, dst)
+}
+
+// Err returns error that occurred while iterating pages
+func (it *PageIterator) Err() error {
+	return it.err
+}
+
+// Close stops iteration and cancel pending page requests
+func (it *PageIterator) Close() {
+	it.cancel()
+	if it.pages != nil {
+		// Drain channel so prefetching goroutine is not blocked
+		for range it.pages {
+		}
+	}
+}
+
+func (it *PageIterator) prefetchPages() {
+	defer close(it.pages)
+	for {
+		page, err := it.fetch()
+		if page == nil && err == nil {
+			return
+		}
+		select {
+		case it.pages <- pageResult{page: page, err: err}:
+		case <-it.ctx.Done():
+			return
+		}
+		if err != nil {
+			return
+		}
+	}
+}
+
+// fetch retrieve next page. It returns nil page if there are no more pages
+func (it *PageIterator) fetch() (*Page, error) {
+	p := it.paginator
+	if it.next == nil || (p.maxPages > 0 && it.count >= p.maxPages) {
+		return nil, nil
+	}
+	if err := it.ctx.Err(); err != nil {
+		return nil, err
+	}
+	// Compose request options
+	endpointPath := p.endpointPath
+	if it.next.EndpointPath != "" {
+		endpointPath = it.next.EndpointPath
+	}
+	args := append(make([]SetRequestOptionFn, 0, len(p.args)+1), p.args...)
+	args = append(args, setQuery(it.next.Query, it.next.ResetQuery))
+	// Do request
+	resp, body, err := p.client.DoRequest(it.ctx, p.method, endpointPath, args...)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return nil, fmt.Errorf("httpc: Unexpected page response status. Page = %d, Status = %s", it.count+1, resp.Status)
+	}
+	// Extract items
+	items, ok := lookupJsonField(body, p.itemsField)
+	if !ok {
+		return nil, fmt.Errorf("httpc: Items field not found in page response body. Field = %s", p.itemsField)
+	}
+	var rawItems []json.RawMessage
+	err = json.Unmarshal(items, &rawItems)
+	if err != nil {
+		return nil, fmt.Errorf("httpc: Failed to parse page items. Field = %s, Error = %w", p.itemsField, err)
+	}
+	it.count++
+	page := &Page{
+		Number:    it.count,
+		Response:  resp,
+		Body:      body,
+		Items:     items,
+		ItemCount: len(rawItems),
+	}
+	// Resolve next page
+	it.next, err = p.strategy.NextPage(page)
+	if err != nil {
+		return nil, err
+	}
+	return page, nil
+}
+
+// PageNumberStrategy creates a strategy that paginate using page number and limit query parameters.
+// Iteration stops when a page returns fewer items than limit
+func PageNumberStrategy(pageParam string, limitParam string, firstPage int, limit int) PageStrategy {
+	return &pageNumberStrategy{
+		pageParam:  pageParam,
+		limitParam: limitParam,
+		firstPage:  firstPage,
+		limit:      limit,
+	}
+}
+
+type pageNumberStrategy struct {
+	pageParam  string
+	limitParam string
+	firstPage  int
+	limit      int
+}
+
+func (s *pageNumberStrategy) FirstPage() *PageRequest {
+	return s.pageRequest(s.firstPage)
+}
+
+func (s *pageNumberStrategy) NextPage(p *Page) (*PageRequest, error) {
+	if p.ItemCount == 0 || p.ItemCount < s.limit {
+		return nil, nil
+	}
+	return s.pageRequest(s.firstPage + p.Number), nil
+}
+
+func (s *pageNumberStrategy) pageRequest(page int) *PageRequest {
+	q := make(url.Values)
+	q.Set(s.pageParam, strconv.Itoa(page))
+	if s.limitParam != "" {
+		q.Set(s.limitParam, strconv.Itoa(s.limit))
+	}
+	return &PageRequest{Query: q}
+}
+
+// CursorStrategy creates a strategy that paginate using cursor token. Cursor is read from dot-separated cursorField
+// in JSON response body and sent in cursorParam query parameter. Iteration stops when cursor is empty or null
+func CursorStrategy(cursorParam string, cursorField string) PageStrategy {
+	return &cursorStrategy{
+		cursorParam: cursorParam,
+		cursorField: cursorField,
+	}
+}
+
+type cursorStrategy struct {
+	cursorParam string
+	cursorField string
+}
+
+func (s *cursorStrategy) FirstPage() *PageRequest {
+	return &PageRequest{}
+}
+
+func (s *cursorStrategy) NextPage(p *Page) (*PageRequest, error) {
+	raw, ok := lookupJsonField(p.Body, s.cursorField)
+	if !ok {
+		return nil, nil
+	}
+	var cursor interface{}
+	err := json.Unmarshal(raw, &cursor)
+	if err != nil {
+		return nil, fmt.Errorf("httpc: Failed to parse cursor. Field = %s, Error = %w", s.cursorField, err)
+	}
+	var v string
+	switch c := cursor.(type) {
+	case nil:
+		return nil, nil
+	case string:
+		v = c
+	case float64:
+		v = strconv.FormatFloat(c, 'f', -1, 64)
+	default:
+		return nil, fmt.Errorf("httpc: Unsupported cursor type. Field = %s, Type = %T", s.cursorField, cursor)
+	}
+	if v == "" {
+		return nil, nil
+	}
+	q := make(url.Values)
+	q.Set(s.cursorParam, v)
+	return &PageRequest{Query: q}, nil
+}
+
+// LinkHeaderStrategy creates a strategy that paginate by following RFC 8288 Link header with rel="next".
+// Relative link is resolved against the url of current page. Next page URL must be under Client base url
+func LinkHeaderStrategy(c *Client) PageStrategy {
+	base := *c.base
+	return &linkHeaderStrategy{base: &base}
+}
+
+type linkHeaderStrategy struct {
+	base *url.URL
+}
+
+func (s *linkHeaderStrategy) FirstPage() *PageRequest {
+	return &PageRequest{}
+}
+
+func (s *linkHeaderStrategy) NextPage(p *Page) (*PageRequest, error) {
+	next := parseNextLink(p.Response.Header.Values("Link"))
+	if next == "" {
+		return nil, nil
+	}
+	ref, err := url.Parse(next)
+	if err != nil {
+		return nil, fmt.Errorf("httpc: Failed to parse next page url. Url = %s, Error = %w", next, err)
+	}
+	u := ref
+	if p.Response.Request != nil {
+		u = p.Response.Request.URL.ResolveReference(ref)
+	}
+	// Validate next page url is under base url
+	basePath := strings.TrimSuffix(s.base.EscapedPath(), "/")
+	endpointPath := strings.TrimPrefix(u.EscapedPath(), basePath)
+	if !strings.EqualFold(u.Scheme, s.base.Scheme) || !strings.EqualFold(u.Host, s.base.Host) ||
+		!strings.HasPrefix(u.EscapedPath(), basePath) || endpointPath != "" && endpointPath[0] != '/' {
+		return nil, fmt.Errorf("httpc: Next page url is not under client base url. Url = %s", u)
+	}
+	if endpointPath == "" {
+		endpointPath = "/"
+	}
+	return &PageRequest{
+		EndpointPath: endpointPath,
+		Query:        u.Query(),
+		ResetQuery:   true,
+	}, nil
+}
+
+// parseNextLink returns url with rel="next" in Link header values
+func parseNextLink(values []string) string {
+	for _, v := range values {
+		for _, link := range strings.Split(v, ",") {
+			segments := strings.Split(link, ";")
+			target := strings.TrimSpace(segments[0])
+			if !strings.HasPrefix(target, "<") || !strings.HasSuffix(target, ">") {
+				continue
+			}
+			for _, param := range segments[1:] {
+				param = strings.TrimSpace(param)
+				if !strings.HasPrefix(strings.ToLower(param), "rel=") {
+					continue
+				}
+				for _, rel := range strings.Fields(strings.Trim(param[4:], `"`)) {
+					if strings.EqualFold(rel, "next") {
+						return target[1 : len(target)-1]
+					}
+				}
+			}
+		}
+	}
+	return ""
+}
+
+// lookupJsonField retrieve raw value of dot-separated field path in JSON body. Empty path returns body
+func lookupJsonField(body []byte, path string) (json.RawMessage, bool) {
+	raw := json.RawMessage(body)
+	if path == "" {
+		return raw, true
+	}
+	for _, key := range strings.Split(path, ".") {
+		var obj map[string]json.RawMessage
+		if err := json.Unmarshal(raw, &obj); err != nil {
+			return nil, false
+		}
+		v, ok := obj[key]
+		if !ok {
+			return nil, false
+		}
+		raw = v
+	}
+	return raw, true
+}
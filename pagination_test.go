@@ -0,0 +1,200 @@
+package httpc_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/nbs-go/httpc"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+type PageItem struct {
+	Id int `json:"id"`
+}
+
+// newPageServer creates a server that serves 7 items
+func newPageServer() *httptest.Server {
+	items := make([]PageItem, 7)
+	for i := range items {
+		items[i] = PageItem{Id: i + 1}
+	}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/page", func(w http.ResponseWriter, r *http.Request) {
+		page, _ := strconv.Atoi(r.URL.Query().Get("page"))
+		limit, _ := strconv.Atoi(r.URL.Query().Get("limit"))
+		start := (page - 1) * limit
+		end := start + limit
+		if start > len(items) {
+			start = len(items)
+		}
+		if end > len(items) {
+			end = len(items)
+		}
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{"data": items[start:end]})
+	})
+	mux.HandleFunc("/cursor", func(w http.ResponseWriter, r *http.Request) {
+		start, _ := strconv.Atoi(r.URL.Query().Get("cursor"))
+		end := start + 3
+		var next interface{}
+		if end < len(items) {
+			next = strconv.Itoa(end)
+		} else {
+			end = len(items)
+		}
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{
+			"result": map[string]interface{}{"items": items[start:end]},
+			"meta":   map[string]interface{}{"next": next},
+		})
+	})
+	var srv *httptest.Server
+	mux.HandleFunc("/link", func(w http.ResponseWriter, r *http.Request) {
+		start, _ := strconv.Atoi(r.URL.Query().Get("offset"))
+		end := start + 3
+		if end < len(items) {
+			w.Header().Set("Link", fmt.Sprintf(`<%s/link?offset=%d>; rel="next", <%s/link?offset=6>; rel="last"`,
+				srv.URL, end, srv.URL))
+		} else {
+			end = len(items)
+		}
+		_ = json.NewEncoder(w).Encode(items[start:end])
+	})
+	srv = httptest.NewServer(mux)
+	return srv
+}
+
+func TestPaginatePageNumber(t *testing.T) {
+	srv := newPageServer()
+	defer srv.Close()
+	pc := httpc.MustNewClient(srv.URL)
+
+	var result []PageItem
+	err := httpc.NewRESTRequest(pc, "GET", "/page").
+		Paginate(httpc.PageNumberStrategy("page", "limit", 1, 3)).
+		ItemsField("data").
+		All(context.Background(), &result)
+	if err != nil {
+		t.Errorf("unexpected error: %s", err)
+		return
+	}
+	if len(result) != 7 || result[6].Id != 7 {
+		t.Errorf("unexpected result: %v", result)
+	}
+}
+
+func TestPaginateCursorPrefetch(t *testing.T) {
+	srv := newPageServer()
+	defer srv.Close()
+	pc := httpc.MustNewClient(srv.URL)
+
+	it := httpc.NewPaginator(pc, "GET", "/cursor", httpc.CursorStrategy("cursor", "meta.next")).
+		ItemsField("result.items").
+		Prefetch(2).
+		Iterate(context.Background())
+	defer it.Close()
+	var ids []int
+	for it.Next() {
+		var items []PageItem
+		if err := it.Scan(&items); err != nil {
+			t.Errorf("unexpected error: %s", err)
+			return
+		}
+		for _, item := range items {
+			ids = append(ids, item.Id)
+		}
+	}
+	if err := it.Err(); err != nil {
+		t.Errorf("unexpected error: %s", err)
+		return
+	}
+	if len(ids) != 7 {
+		t.Errorf("unexpected item count: %d", len(ids))
+	}
+}
+
+func TestPaginateLinkHeaderMaxPages(t *testing.T) {
+	srv := newPageServer()
+	defer srv.Close()
+	pc := httpc.MustNewClient(srv.URL)
+
+	var result []PageItem
+	err := httpc.NewPaginator(pc, "GET", "/link", httpc.LinkHeaderStrategy(pc)).
+		MaxPages(2).
+		All(context.Background(), &result)
+	if err != nil {
+		t.Errorf("unexpected error: %s", err)
+		return
+	}
+	if len(result) != 6 {
+		t.Errorf("unexpected item count: %d", len(result))
+	}
+}
+
+func TestPaginateEarlyStop(t *testing.T) {
+	srv := newPageServer()
+	defer srv.Close()
+	pc := httpc.MustNewClient(srv.URL)
+
+	it := httpc.NewPaginator(pc, "GET", "/link", httpc.LinkHeaderStrategy(pc)).
+		Prefetch(1).
+		Iterate(context.Background())
+	if !it.Next() {
+		t.Errorf("unexpected condition: first page is not fetched. Error = %v", it.Err())
+		return
+	}
+	it.Close()
+	if it.Page().Number != 1 {
+		t.Errorf("unexpected page number: %d", it.Page().Number)
+	}
+}
+
+func TestPaginateInvalidDestination(t *testing.T) {
+	var result []PageItem
+	err := httpc.NewPaginator(c, "GET", "/", httpc.CursorStrategy("cursor", "next")).
+		All(context.Background(), result)
+	if err == nil || err.Error() != "httpc: Paginator.All() dst must be a pointer to slice. Type = []httpc_test.PageItem" {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
+func TestPaginateLinkHeaderRelative(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/v1/items", func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Query().Get("page") {
+		case "":
+			w.Header().Set("Link", `<?page=2>; rel="next"`)
+			_, _ = w.Write([]byte(`[{"id":1}]`))
+		case "2":
+			w.Header().Set("Link", `</v1/items?page=3>; rel="next"`)
+			_, _ = w.Write([]byte(`[{"id":2}]`))
+		case "3":
+			w.Header().Set("Link", `</v2/items?page=4>; rel="next"`)
+			_, _ = w.Write([]byte(`[{"id":3}]`))
+		}
+	})
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+	pc := httpc.MustNewClient(srv.URL + "/v1")
+
+	var result []PageItem
+	err := httpc.NewPaginator(pc, "GET", "/items", httpc.LinkHeaderStrategy(pc)).
+		MaxPages(2).
+		All(context.Background(), &result)
+	if err != nil {
+		t.Errorf("unexpected error: %s", err)
+		return
+	}
+	if len(result) != 2 || result[1].Id != 2 {
+		t.Errorf("unexpected result: %v", result)
+	}
+
+	// Assert link outside base url is rejected
+	err = httpc.NewPaginator(pc, "GET", "/items", httpc.LinkHeaderStrategy(pc)).
+		All(context.Background(), &result)
+	if err == nil || !strings.Contains(err.Error(), "httpc: Next page url is not under client base url") {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
@@ -0,0 +1,105 @@
+package httpc
+
+import (
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+)
+
+var pathParamPattern = regexp.MustCompile(`\{([^{}/]+)}`)
+
+// resolvePath replace {name} placeholders in endpoint path template with escaped path parameter values.
+// It returns error if a placeholder has no value or the value is a dot segment
+func resolvePath(template string, params map[string]string) (string, error) {
+	var err error
+	p := pathParamPattern.ReplaceAllStringFunc(template, func(m string) string {
+		name := m[1 : len(m)-1]
+		v, ok := params[name]
+		if !ok {
+			if err == nil {
+				err = fmt.Errorf("httpc: Unresolved path parameter in endpoint path. Param = %s, Path = %s", name, template)
+			}
+			return m
+		}
+		// Dot segments are not escaped by url.PathEscape and would be removed when url is resolved
+		if v == "." || v == ".." {
+			if err == nil {
+				err = fmt.Errorf("httpc: Invalid path parameter value, dot segment is not allowed. Param = %s, Value = %s", name, v)
+			}
+			return m
+		}
+		return url.PathEscape(v)
+	})
+	if err != nil {
+		return "", err
+	}
+	return p, nil
+}
+
+// parseBaseUrl parse and validate Client base url
+func parseBaseUrl(baseUrl string) (*url.URL, error) {
+	u, err := url.Parse(baseUrl)
+	if err != nil {
+		return nil, fmt.Errorf("httpc: Invalid base url. BaseUrl = %s, Error = %w", baseUrl, err)
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return nil, fmt.Errorf("httpc: Invalid base url, scheme must be http or https. BaseUrl = %s", baseUrl)
+	}
+	if u.Host == "" {
+		return nil, fmt.Errorf("httpc: Invalid base url, host is required. BaseUrl = %s", baseUrl)
+	}
+	if u.RawQuery != "" || u.Fragment != "" {
+		return nil, fmt.Errorf("httpc: Invalid base url, query and fragment are not allowed. BaseUrl = %s", baseUrl)
+	}
+	return u, nil
+}
+
+// resolveUrl join endpoint path to base url following url.URL.ResolveReference rules, with base url treated as
+// a directory. Query parameters in endpoint path are merged with query
+func resolveUrl(base *url.URL, endpointPath string, query url.Values, allowAbsolute bool) (*url.URL, error) {
+	ref, err := url.Parse(endpointPath)
+	if err != nil {
+		return nil, fmt.Errorf("httpc: Invalid endpoint path. Path = %s, Error = %w", endpointPath, err)
+	}
+	var u *url.URL
+	switch {
+	case ref.Scheme != "" || ref.Host != "":
+		if !allowAbsolute {
+			return nil, fmt.Errorf("httpc: Absolute endpoint url is not allowed. Url = %s", endpointPath)
+		}
+		u = ref
+	case ref.Path == "":
+		// Keep base path as is
+		uc := *base
+		uc.RawQuery = ref.RawQuery
+		u = &uc
+	default:
+		// Ensure base path is a directory, so the last segment of base path is not replaced
+		dir := *base
+		if !strings.HasSuffix(dir.Path, "/") {
+			dir.Path += "/"
+			if dir.RawPath != "" {
+				dir.RawPath += "/"
+			}
+		}
+		// Resolve endpoint path relative to base directory. Prefix "./" prevents the first segment from being parsed as scheme
+		ref, err = url.Parse("./" + strings.TrimLeft(endpointPath, "/"))
+		if err != nil {
+			return nil, fmt.Errorf("httpc: Invalid endpoint path. Path = %s, Error = %w", endpointPath, err)
+		}
+		u = dir.ResolveReference(ref)
+	}
+	u.Fragment = ""
+	// Merge query
+	if len(query) > 0 {
+		q := u.Query()
+		for k, values := range query {
+			for _, v := range values {
+				q.Add(k, v)
+			}
+		}
+		u.RawQuery = q.Encode()
+	}
+	return u, nil
+}
@@ -0,0 +1,224 @@
+package httpc
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DefaultDurationBuckets are request duration histogram buckets in seconds
+var DefaultDurationBuckets = []float64{0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10}
+
+// DefaultSizeBuckets are request and response size histogram buckets in bytes
+var DefaultSizeBuckets = []float64{100, 1000, 10000, 100000, 1000000, 10000000}
+
+const prometheusContentType = "text/plain; version=0.0.4; charset=utf-8"
+
+type SetPrometheusOptionFn func(m *PrometheusMetrics)
+
+// PrometheusPrefix set metric name prefix. Default is "httpc"
+func PrometheusPrefix(prefix string) SetPrometheusOptionFn {
+	return func(m *PrometheusMetrics) {
+		m.prefix = prefix
+	}
+}
+
+// PrometheusDurationBuckets set request duration histogram buckets in seconds
+func PrometheusDurationBuckets(buckets ...float64) SetPrometheusOptionFn {
+	return func(m *PrometheusMetrics) {
+		m.durationBuckets = buckets
+	}
+}
+
+// PrometheusSizeBuckets set request and response size histogram buckets in bytes
+func PrometheusSizeBuckets(buckets ...float64) SetPrometheusOptionFn {
+	return func(m *PrometheusMetrics) {
+		m.sizeBuckets = buckets
+	}
+}
+
+// NewPrometheusMetrics creates Metrics that expose collected metrics in Prometheus text exposition format.
+// Use it as http.Handler to serve scrape requests, or WriteTo to write metrics
+func NewPrometheusMetrics(args ...SetPrometheusOptionFn) *PrometheusMetrics {
+	m := PrometheusMetrics{
+		prefix:          "httpc",
+		durationBuckets: DefaultDurationBuckets,
+		sizeBuckets:     DefaultSizeBuckets,
+		requests:        make(map[MetricLabels]float64),
+		inFlight:        make(map[MetricLabels]float64),
+		durations:       make(map[MetricLabels]*histogram),
+		requestSizes:    make(map[MetricLabels]*histogram),
+		responseSizes:   make(map[MetricLabels]*histogram),
+	}
+	for _, fn := range args {
+		fn(&m)
+	}
+	// Sort copies, so caller and default buckets are not modified
+	m.durationBuckets = sortedBuckets(m.durationBuckets)
+	m.sizeBuckets = sortedBuckets(m.sizeBuckets)
+	return &m
+}
+
+func sortedBuckets(buckets []float64) []float64 {
+	b := make([]float64, len(buckets))
+	copy(b, buckets)
+	sort.Float64s(b)
+	return b
+}
+
+// PrometheusMetrics collects request count, in-flight requests, request duration and request and response size
+type PrometheusMetrics struct {
+	prefix          string
+	durationBuckets []float64
+	sizeBuckets     []float64
+	mu              sync.Mutex
+	requests        map[MetricLabels]float64
+	inFlight        map[MetricLabels]float64
+	durations       map[MetricLabels]*histogram
+	requestSizes    map[MetricLabels]*histogram
+	responseSizes   map[MetricLabels]*histogram
+}
+
+type histogram struct {
+	counts []uint64
+	sum    float64
+	count  uint64
+}
+
+func (h *histogram) observe(buckets []float64, v float64) {
+	if h.counts == nil {
+		h.counts = make([]uint64, len(buckets))
+	}
+	for i, b := range buckets {
+		if v <= b {
+			h.counts[i]++
+		}
+	}
+	h.sum += v
+	h.count++
+}
+
+func (m *PrometheusMetrics) RequestStarted(l MetricLabels) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.inFlight[l]++
+}
+
+func (m *PrometheusMetrics) RequestFinished(l MetricLabels, duration time.Duration, requestSize int, responseSize int) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	// In-flight gauge does not have status class label
+	fl := l
+	fl.StatusClass = ""
+	m.inFlight[fl]--
+	m.requests[l]++
+	observe(m.durations, l, m.durationBuckets, duration.Seconds())
+	observe(m.requestSizes, l, m.sizeBuckets, float64(requestSize))
+	observe(m.responseSizes, l, m.sizeBuckets, float64(responseSize))
+}
+
+func observe(hs map[MetricLabels]*histogram, l MetricLabels, buckets []float64, v float64) {
+	h, ok := hs[l]
+	if !ok {
+		h = new(histogram)
+		hs[l] = h
+	}
+	h.observe(buckets, v)
+}
+
+// WriteTo writes metrics in Prometheus text exposition format to w
+func (m *PrometheusMetrics) WriteTo(w io.Writer) (int64, error) {
+	var b strings.Builder
+	m.mu.Lock()
+	m.writeCounter(&b, "requests_total", "Total number of HTTP requests.", "counter", m.requests)
+	m.writeCounter(&b, "requests_in_flight", "Number of HTTP requests in flight.", "gauge", m.inFlight)
+	m.writeHistogram(&b, "request_duration_seconds", "HTTP request duration in seconds.", m.durationBuckets, m.durations)
+	m.writeHistogram(&b, "request_size_bytes", "HTTP request body size in bytes.", m.sizeBuckets, m.requestSizes)
+	m.writeHistogram(&b, "response_size_bytes", "HTTP response body size in bytes.", m.sizeBuckets, m.responseSizes)
+	m.mu.Unlock()
+	n, err := io.WriteString(w, b.String())
+	return int64(n), err
+}
+
+// ServeHTTP serves scrape request
+func (m *PrometheusMetrics) ServeHTTP(w http.ResponseWriter, _ *http.Request) {
+	w.Header().Set(HeaderContentType, prometheusContentType)
+	_, _ = m.WriteTo(w)
+}
+
+func (m *PrometheusMetrics) writeCounter(b *strings.Builder, name string, help string, typ string, values map[MetricLabels]float64) {
+	name = m.prefix + "_" + name
+	_, _ = fmt.Fprintf(b, "# HELP %s %s\n# TYPE %s %s\n", name, help, name, typ)
+	for _, l := range sortedLabels(values) {
+		_, _ = fmt.Fprintf(b, "%s{%s} %s\n", name, formatLabels(l, ""), formatFloat(values[l]))
+	}
+}
+
+func (m *PrometheusMetrics) writeHistogram(b *strings.Builder, name string, help string, buckets []float64,
+	values map[MetricLabels]*histogram) {
+	name = m.prefix + "_" + name
+	_, _ = fmt.Fprintf(b, "# HELP %s %s\n# TYPE %s histogram\n", name, help, name)
+	labels := make([]MetricLabels, 0, len(values))
+	for l := range values {
+		labels = append(labels, l)
+	}
+	sortLabels(labels)
+	for _, l := range labels {
+		h := values[l]
+		for i, le := range buckets {
+			_, _ = fmt.Fprintf(b, "%s_bucket{%s} %d\n", name, formatLabels(l, formatFloat(le)), h.counts[i])
+		}
+		_, _ = fmt.Fprintf(b, "%s_bucket{%s} %d\n", name, formatLabels(l, "+Inf"), h.count)
+		_, _ = fmt.Fprintf(b, "%s_sum{%s} %s\n", name, formatLabels(l, ""), formatFloat(h.sum))
+		_, _ = fmt.Fprintf(b, "%s_count{%s} %d\n", name, formatLabels(l, ""), h.count)
+	}
+}
+
+func sortedLabels(values map[MetricLabels]float64) []MetricLabels {
+	labels := make([]MetricLabels, 0, len(values))
+	for l := range values {
+		labels = append(labels, l)
+	}
+	sortLabels(labels)
+	return labels
+}
+
+func sortLabels(labels []MetricLabels) {
+	sort.Slice(labels, func(i, j int) bool {
+		return formatLabels(labels[i], "") < formatLabels(labels[j], "")
+	})
+}
+
+// formatLabels returns labels in exposition format. Status class label is omitted if empty, le label is added if set
+func formatLabels(l MetricLabels, le string) string {
+	pairs := []string{
+		"namespace=" + quoteLabel(l.Namespace),
+		"method=" + quoteLabel(l.Method),
+		"endpoint=" + quoteLabel(l.Endpoint),
+		"host=" + quoteLabel(l.Host),
+	}
+	if l.StatusClass != "" {
+		pairs = append(pairs, "status_class="+quoteLabel(l.StatusClass))
+	}
+	if le != "" {
+		pairs = append(pairs, "le="+quoteLabel(le))
+	}
+	return strings.Join(pairs, ",")
+}
+
+func quoteLabel(v string) string {
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`).Replace(v) + `"`
+}
+
+func formatFloat(v float64) string {
+	if math.IsInf(v, 1) {
+		return "+Inf"
+	}
+	return strconv.FormatFloat(v, 'g', -1, 64)
+}
@@ -0,0 +1,89 @@
+package httpc_test
+
+import (
+	"context"
+	"github.com/nbs-go/httpc"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestPrometheusMetrics(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/items/2" {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		_, _ = w.Write([]byte("hello"))
+	}))
+	defer srv.Close()
+	m := httpc.NewPrometheusMetrics(httpc.PrometheusDurationBuckets(60))
+	rc := httpc.MustNewClient(srv.URL, httpc.Namespace("catalog"), httpc.CollectMetrics(m))
+
+	for _, id := range []string{"1", "1", "2"} {
+		_, _, err := rc.DoRequest(context.Background(), "GET", "/items/{id}", httpc.PathParam("id", id))
+		if err != nil {
+			t.Errorf("unexpected error: %s", err)
+			return
+		}
+	}
+
+	// Scrape metrics
+	scraper := httptest.NewServer(m)
+	defer scraper.Close()
+	resp, err := http.Get(scraper.URL)
+	if err != nil {
+		t.Errorf("unexpected error: %s", err)
+		return
+	}
+	defer func() { _ = resp.Body.Close() }()
+	b, _ := io.ReadAll(resp.Body)
+	text := string(b)
+	host := srv.Listener.Addr().String()
+	labels := `namespace="catalog",method="GET",endpoint="/items/{id}",host="` + host + `"`
+	expected := []string{
+		"# TYPE httpc_requests_total counter",
+		"httpc_requests_total{" + labels + `,status_class="2xx"} 2`,
+		"httpc_requests_total{" + labels + `,status_class="5xx"} 1`,
+		"httpc_requests_in_flight{" + labels + "} 0",
+		"httpc_request_duration_seconds_bucket{" + labels + `,status_class="2xx",le="60"} 2`,
+		"httpc_request_duration_seconds_bucket{" + labels + `,status_class="2xx",le="+Inf"} 2`,
+		"httpc_response_size_bytes_sum{" + labels + `,status_class="2xx"} 10`,
+		"httpc_response_size_bytes_count{" + labels + `,status_class="5xx"} 1`,
+	}
+	for _, s := range expected {
+		if !strings.Contains(text, s+"\n") {
+			t.Errorf("unexpected condition: %s is not found in metrics.\n%s", s, text)
+		}
+	}
+	if ct := resp.Header.Get(httpc.HeaderContentType); !strings.HasPrefix(ct, "text/plain; version=0.0.4") {
+		t.Errorf("unexpected content type: %s", ct)
+	}
+}
+
+func TestPrometheusMetricsBuckets(t *testing.T) {
+	buckets := []float64{1000, 10}
+	defaults := append([]float64{}, httpc.DefaultSizeBuckets...)
+	m := httpc.NewPrometheusMetrics(httpc.PrometheusDurationBuckets(buckets...))
+	m.RequestFinished(httpc.MetricLabels{Method: "GET"}, 0, 0, 0)
+
+	// Assert caller and default buckets are not sorted in place
+	if buckets[0] != 1000 || buckets[1] != 10 {
+		t.Errorf("unexpected condition: caller buckets are modified. Buckets = %v", buckets)
+	}
+	for i, b := range httpc.DefaultSizeBuckets {
+		if b != defaults[i] {
+			t.Errorf("unexpected condition: default buckets are modified. Buckets = %v", httpc.DefaultSizeBuckets)
+			break
+		}
+	}
+	var b strings.Builder
+	_, _ = m.WriteTo(&b)
+	text := b.String()
+	i := strings.Index(text, `httpc_request_duration_seconds_bucket{namespace="",method="GET",endpoint="",host="",le="10"}`)
+	if i < 0 || i > strings.Index(text, `le="1000"`) {
+		t.Errorf("unexpected condition: duration buckets are not sorted.\n%s", text)
+	}
+}
@@ -0,0 +1,236 @@
+package httpc
+
+import (
+	"bytes"
+	"encoding/json"
+	"mime"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+)
+
+// cookieValuePattern matches cookie name and value pairs in Cookie and Set-Cookie header
+var cookieValuePattern = regexp.MustCompile(`([^=;\s]+)=([^;]*)`)
+
+// DefaultRedactionMask is the replacement of redacted values if Redaction.Mask is not set
+const DefaultRedactionMask = "***"
+
+// Redaction configures sensitive data that is masked in log dumps
+type Redaction struct {
+	// Headers is list of header keys which values are masked. Cookie and Set-Cookie header only mask cookie values
+	Headers []string
+	// QueryParams is list of query parameter names which values are masked
+	QueryParams []string
+	// Fields is list of JSON and form body fields which values are masked. A field name matches at any depth,
+	// while a dot-separated path, optionally prefixed with "$.", matches from the root object, e.g. "$.user.password"
+	Fields []string
+	// Patterns is list of regular expressions, matches in dump text are masked
+	Patterns []*regexp.Regexp
+	// Mask is the replacement of redacted values. If empty, DefaultRedactionMask is used
+	Mask string
+}
+
+// DefaultRedaction returns redaction that mask common credentials in headers, query parameters and body fields
+func DefaultRedaction() Redaction {
+	return Redaction{
+		Headers: []string{
+			HeaderAuthorization, "Proxy-Authorization", "Cookie", "Set-Cookie", "X-Api-Key", HeaderAmzSecurityToken,
+		},
+		QueryParams: []string{
+			"access_token", "api_key", "apikey", "password", "X-Amz-Signature", "X-Amz-Security-Token",
+		},
+		Fields: []string{
+			"password", "secret", "client_secret", "access_token", "refresh_token",
+		},
+	}
+}
+
+// redactor applies Redaction to log dumps
+type redactor struct {
+	headers map[string]bool
+	query   map[string]bool
+	names   map[string]bool
+	paths   [][]string
+	regexps []*regexp.Regexp
+	mask    string
+}
+
+func newRedactor(r *Redaction) *redactor {
+	rd := redactor{
+		headers: make(map[string]bool),
+		query:   make(map[string]bool),
+		names:   make(map[string]bool),
+		regexps: r.Patterns,
+		mask:    r.Mask,
+	}
+	if rd.mask == "" {
+		rd.mask = DefaultRedactionMask
+	}
+	for _, h := range r.Headers {
+		rd.headers[http.CanonicalHeaderKey(h)] = true
+	}
+	for _, q := range r.QueryParams {
+		rd.query[strings.ToLower(q)] = true
+	}
+	for _, f := range r.Fields {
+		f = strings.TrimPrefix(f, "$.")
+		if strings.Contains(f, ".") {
+			rd.paths = append(rd.paths, strings.Split(f, "."))
+		} else {
+			rd.names[f] = true
+		}
+	}
+	return &rd
+}
+
+// redactHeader mask values of header in place
+func (rd *redactor) redactHeader(h http.Header) {
+	for k, values := range h {
+		ck := http.CanonicalHeaderKey(k)
+		if !rd.headers[ck] {
+			continue
+		}
+		for i, v := range values {
+			switch ck {
+			case "Cookie":
+				values[i] = cookieValuePattern.ReplaceAllString(v, "$1="+rd.mask)
+			case "Set-Cookie":
+				values[i] = rd.maskSetCookie(v)
+			default:
+				values[i] = rd.mask
+			}
+		}
+	}
+}
+
+func (rd *redactor) maskSetCookie(v string) string {
+	loc := cookieValuePattern.FindStringSubmatchIndex(v)
+	if loc == nil {
+		return v
+	}
+	return v[:loc[4]] + rd.mask + v[loc[5]:]
+}
+
+// redactUrl returns copy of url with masked query parameters
+func (rd *redactor) redactUrl(u *url.URL) *url.URL {
+	if u.RawQuery == "" || len(rd.query) == 0 {
+		return u
+	}
+	q := u.Query()
+	masked := false
+	for k, values := range q {
+		if !rd.query[strings.ToLower(k)] {
+			continue
+		}
+		for i := range values {
+			values[i] = rd.mask
+		}
+		masked = true
+	}
+	if !masked {
+		return u
+	}
+	uc := *u
+	uc.RawQuery = q.Encode()
+	return &uc
+}
+
+// redactBody returns body with masked fields based on content type. Body of unsupported content type is returned as is
+func (rd *redactor) redactBody(contentType string, body []byte) []byte {
+	if len(body) == 0 || (len(rd.names) == 0 && len(rd.paths) == 0) {
+		return body
+	}
+	mt, _, _ := mime.ParseMediaType(contentType)
+	switch {
+	case mt == MimeTypeJson || strings.HasSuffix(mt, "+json"):
+		var v interface{}
+		d := json.NewDecoder(bytes.NewReader(body))
+		d.UseNumber()
+		if err := d.Decode(&v); err != nil {
+			return body
+		}
+		if !rd.redactJson(v, nil) {
+			return body
+		}
+		b, err := json.Marshal(v)
+		if err != nil {
+			return body
+		}
+		return b
+	case mt == MimeTypeUrlEncodedForm:
+		form, err := url.ParseQuery(string(body))
+		if err != nil {
+			return body
+		}
+		masked := false
+		for k, values := range form {
+			if !rd.names[k] && !rd.matchPath([]string{k}) {
+				continue
+			}
+			for i := range values {
+				values[i] = rd.mask
+			}
+			masked = true
+		}
+		if !masked {
+			return body
+		}
+		return []byte(form.Encode())
+	}
+	return body
+}
+
+// redactJson mask matched fields in decoded JSON value in place. It returns true if a field is masked
+func (rd *redactor) redactJson(v interface{}, path []string) bool {
+	masked := false
+	switch t := v.(type) {
+	case map[string]interface{}:
+		for k, child := range t {
+			p := append(path[:len(path):len(path)], k)
+			if rd.names[k] || rd.matchPath(p) {
+				t[k] = rd.mask
+				masked = true
+				continue
+			}
+			if rd.redactJson(child, p) {
+				masked = true
+			}
+		}
+	case []interface{}:
+		// Array items share the path of array
+		for _, child := range t {
+			if rd.redactJson(child, path) {
+				masked = true
+			}
+		}
+	}
+	return masked
+}
+
+func (rd *redactor) matchPath(p []string) bool {
+	for _, rp := range rd.paths {
+		if len(rp) != len(p) {
+			continue
+		}
+		matched := true
+		for i := range rp {
+			if rp[i] != p[i] {
+				matched = false
+				break
+			}
+		}
+		if matched {
+			return true
+		}
+	}
+	return false
+}
+
+// redactText mask regular expression matches in dump text
+func (rd *redactor) redactText(b []byte) []byte {
+	for _, re := range rd.regexps {
+		b = re.ReplaceAll(b, []byte(rd.mask))
+	}
+	return b
+}
@@ -0,0 +1,89 @@
+package httpc_test
+
+import (
+	"context"
+	"github.com/nbs-go/httpc"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"regexp"
+	"strings"
+	"testing"
+)
+
+func TestLogDumpDefaultRedaction(t *testing.T) {
+	l, restore := newCaptureLogger()
+	defer restore()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.SetCookie(w, &http.Cookie{Name: "session", Value: "s3cret-session", Path: "/"})
+		w.Header().Set(httpc.HeaderContentType, httpc.MimeTypeJson)
+		_, _ = w.Write([]byte(`{"access_token":"s3cret-token","user":{"name":"john"}}`))
+	}))
+	defer srv.Close()
+	rc := httpc.MustNewClient(srv.URL, httpc.LogDump(true), httpc.ClientBearerToken("s3cret-bearer"))
+
+	_, _, err := rc.DoRequest(context.Background(), "POST", "/login",
+		httpc.AddQuery("api_key", "s3cret-key", "page", "1"),
+		httpc.SetJsonBody(map[string]interface{}{"username": "john", "password": "s3cret-password"}),
+	)
+	if err != nil {
+		t.Errorf("unexpected error: %s", err)
+		return
+	}
+	reqDump := l.Entries("HTTP Request Dump")
+	respDump := l.Entries("HTTP Response Dump")
+	if len(reqDump) != 1 || len(respDump) != 1 {
+		t.Errorf("unexpected dump count. Request = %d, Response = %d", len(reqDump), len(respDump))
+		return
+	}
+	dump := reqDump[0].msg + respDump[0].msg
+	if strings.Contains(dump, "s3cret") {
+		t.Errorf("unexpected condition: sensitive data is not redacted.\n%s", dump)
+	}
+	for _, s := range []string{"Authorization: ***", "api_key=%2A%2A%2A", `"password":"***"`, "Set-Cookie: session=***; Path=/", `"name":"john"`} {
+		if !strings.Contains(dump, s) {
+			t.Errorf("unexpected condition: %s is not found in dump.\n%s", s, dump)
+		}
+	}
+}
+
+func TestLogDumpCustomRedaction(t *testing.T) {
+	l, restore := newCaptureLogger()
+	defer restore()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer srv.Close()
+	rc := httpc.MustNewClient(srv.URL, httpc.LogDump(true), httpc.LogDumpRedaction(httpc.Redaction{
+		Headers:  []string{"X-Signature"},
+		Fields:   []string{"$.card.number", "pin"},
+		Patterns: []*regexp.Regexp{regexp.MustCompile(`\d{3}-\d{4}`)},
+		Mask:     "[REDACTED]",
+	}))
+
+	form := url.Values{"pin": {"123456"}, "note": {"call 555-1234"}}
+	_, _, err := rc.DoRequest(context.Background(), "POST", "/form", httpc.SetUrlEncodedFormBody(form),
+		httpc.AddHeader("X-Signature", "abc", httpc.HeaderAuthorization, "visible"))
+	if err != nil {
+		t.Errorf("unexpected error: %s", err)
+		return
+	}
+	_, _, err = rc.DoRequest(context.Background(), "POST", "/json", httpc.SetJsonBody(map[string]interface{}{
+		"card":   map[string]string{"number": "4111111111111111"},
+		"number": "visible-number",
+	}))
+	if err != nil {
+		t.Errorf("unexpected error: %s", err)
+		return
+	}
+	entries := l.Entries("HTTP Request Dump")
+	if len(entries) != 2 {
+		t.Errorf("unexpected dump count: %d", len(entries))
+		return
+	}
+	dump := entries[0].msg + entries[1].msg
+	for _, s := range []string{"X-Signature: [REDACTED]", "Authorization: visible", "pin=%5BREDACTED%5D", "call+[REDACTED]",
+		`"number":"[REDACTED]"`, `"number":"visible-number"`} {
+		if !strings.Contains(dump, s) {
+			t.Errorf("unexpected condition: %s is not found in dump.\n%s", s, dump)
+		}
+	}
+}
@@ -0,0 +1,149 @@
+package httpc
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// DefaultMaxRedirects is the maximum number of redirects followed if RedirectPolicy.MaxRedirects is not set
+const DefaultMaxRedirects = 10
+
+// forwardedHeaders are headers dropped by http.Client when redirected to a different domain, that are restored if
+// ForwardSensitiveHeaders is set. Cookie is not restored, since it contains cookies of the original host from jar
+var forwardedHeaders = []string{HeaderAuthorization, "Www-Authenticate"}
+
+// RedirectPolicy controls how redirect responses are followed
+type RedirectPolicy struct {
+	// Disabled stops following redirects. The redirect response will be returned without error
+	Disabled bool
+	// MaxRedirects is the maximum number of redirects to follow. If zero, DefaultMaxRedirects is used
+	MaxRedirects int
+	// SameHost only allows redirect to the same scheme, host and port as the original request
+	SameHost bool
+	// AllowedHosts only allows redirect to listed hosts, in addition to the original request host.
+	// Redirect from https to http is not allowed
+	AllowedHosts []string
+	// ForwardSensitiveHeaders forwards Authorization header when redirected to a different domain. By default, the
+	// header is dropped. Header is never forwarded when redirected from https to http, even to the same host
+	ForwardSensitiveHeaders bool
+}
+
+type redirectPolicyKey struct{}
+
+// withRedirectPolicy set redirect policy in request context, so it could be read in http.Client CheckRedirect
+func withRedirectPolicy(ctx context.Context, p *RedirectPolicy) context.Context {
+	if p == nil {
+		return ctx
+	}
+	return context.WithValue(ctx, redirectPolicyKey{}, p)
+}
+
+// checkRedirect returns http.Client CheckRedirect function. Policy in request context takes precedence over
+// Client default policy
+func checkRedirect(defaultPolicy *RedirectPolicy) func(req *http.Request, via []*http.Request) error {
+	return func(req *http.Request, via []*http.Request) error {
+		p, ok := req.Context().Value(redirectPolicyKey{}).(*RedirectPolicy)
+		if !ok {
+			p = defaultPolicy
+		}
+		if p == nil {
+			p = &RedirectPolicy{}
+		}
+		if p.Disabled {
+			return http.ErrUseLastResponse
+		}
+		max := p.MaxRedirects
+		if max <= 0 {
+			max = DefaultMaxRedirects
+		}
+		if len(via) > max {
+			return fmt.Errorf("httpc: Stopped after %d redirects", max)
+		}
+		// Validate redirect host
+		origin := via[0]
+		host := req.URL.Hostname()
+		downgrade := origin.URL.Scheme == "https" && req.URL.Scheme != "https"
+		if !sameOrigin(origin.URL, req.URL) {
+			if p.SameHost {
+				return fmt.Errorf("httpc: Redirect to different host is not allowed. Host = %s", req.URL.Host)
+			}
+			if len(p.AllowedHosts) > 0 {
+				if !strings.EqualFold(host, origin.URL.Hostname()) && !containsFold(p.AllowedHosts, host) {
+					return fmt.Errorf("httpc: Redirect host is not in allowed list. Host = %s", host)
+				}
+				if downgrade {
+					return fmt.Errorf("httpc: Redirect from https to http is not allowed. Host = %s", req.URL.Host)
+				}
+			}
+		}
+		// Drop sensitive headers that are kept by http.Client for the same host name when redirected to plain http
+		if downgrade {
+			for _, k := range forwardedHeaders {
+				req.Header.Del(k)
+			}
+			return nil
+		}
+		// Restore sensitive headers that are dropped by http.Client
+		if p.ForwardSensitiveHeaders {
+			for _, k := range forwardedHeaders {
+				if v, exist := origin.Header[k]; exist && req.Header.Get(k) == "" {
+					req.Header[k] = v
+				}
+			}
+		}
+		return nil
+	}
+}
+
+// RedirectChain returns urls of requests that lead to the response, starting from the original request url and
+// ending with the final url
+func RedirectChain(resp *http.Response) []*url.URL {
+	if resp == nil || resp.Request == nil {
+		return nil
+	}
+	chain := make([]*url.URL, 0, 1)
+	for req := resp.Request; req != nil; {
+		chain = append(chain, req.URL)
+		if req.Response == nil {
+			break
+		}
+		req = req.Response.Request
+	}
+	// Reverse chain order
+	for i, j := 0, len(chain)-1; i < j; i, j = i+1, j-1 {
+		chain[i], chain[j] = chain[j], chain[i]
+	}
+	return chain
+}
+
+// sameOrigin returns true if urls have the same scheme, host and port
+func sameOrigin(a *url.URL, b *url.URL) bool {
+	return strings.EqualFold(a.Scheme, b.Scheme) && strings.EqualFold(a.Hostname(), b.Hostname()) &&
+		urlPort(a) == urlPort(b)
+}
+
+// urlPort returns port of url, or default port of its scheme
+func urlPort(u *url.URL) string {
+	if p := u.Port(); p != "" {
+		return p
+	}
+	switch strings.ToLower(u.Scheme) {
+	case "https":
+		return "443"
+	case "http":
+		return "80"
+	}
+	return ""
+}
+
+func containsFold(values []string, s string) bool {
+	for _, v := range values {
+		if strings.EqualFold(v, s) {
+			return true
+		}
+	}
+	return false
+}
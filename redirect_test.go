@@ -0,0 +1,176 @@
+package httpc_test
+
+import (
+	"context"
+	"github.com/nbs-go/httpc"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+)
+
+func newRedirectServers() (*httptest.Server, *httptest.Server) {
+	other := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(r.Header.Get(httpc.HeaderAuthorization) + r.Header.Get("Cookie")))
+	}))
+	// Use different host name for the other server
+	otherUrl := strings.Replace(other.URL, "127.0.0.1", "localhost", 1)
+	mux := http.NewServeMux()
+	mux.HandleFunc("/a", func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, "/b", http.StatusFound)
+	})
+	mux.HandleFunc("/b", func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, "/c", http.StatusFound)
+	})
+	mux.HandleFunc("/c", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("c"))
+	})
+	mux.HandleFunc("/other", func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, otherUrl+"/", http.StatusTemporaryRedirect)
+	})
+	mux.HandleFunc("/other-port", func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, other.URL+"/", http.StatusTemporaryRedirect)
+	})
+	return httptest.NewServer(mux), other
+}
+
+func TestRedirectChain(t *testing.T) {
+	srv, other := newRedirectServers()
+	defer srv.Close()
+	defer other.Close()
+	rc := httpc.MustNewClient(srv.URL)
+
+	resp, body, err := rc.DoRequest(context.Background(), "GET", "/a")
+	if err != nil {
+		t.Errorf("unexpected error: %s", err)
+		return
+	}
+	if string(body) != "c" {
+		t.Errorf("unexpected body: %s", body)
+	}
+	chain := httpc.RedirectChain(resp)
+	if len(chain) != 3 || chain[0].Path != "/a" || chain[2].Path != "/c" {
+		t.Errorf("unexpected redirect chain: %v", chain)
+	}
+}
+
+func TestRedirectPolicy(t *testing.T) {
+	srv, other := newRedirectServers()
+	defer srv.Close()
+	defer other.Close()
+	rc := httpc.MustNewClient(srv.URL, httpc.ClientRedirect(httpc.RedirectPolicy{MaxRedirects: 1}))
+
+	// Assert disabled redirect
+	resp, _, err := rc.DoRequest(context.Background(), "GET", "/a", httpc.DisableRedirect())
+	if err != nil {
+		t.Errorf("unexpected error: %s", err)
+		return
+	}
+	if resp.StatusCode != http.StatusFound {
+		t.Errorf("unexpected response status code. StatusCode = %d", resp.StatusCode)
+	}
+
+	// Assert max redirects
+	_, _, err = rc.DoRequest(context.Background(), "GET", "/a")
+	if err == nil || !strings.HasSuffix(err.Error(), "httpc: Stopped after 1 redirects") {
+		t.Errorf("unexpected error: %v", err)
+	}
+
+	// Assert same host
+	_, _, err = rc.DoRequest(context.Background(), "GET", "/other", httpc.Redirect(httpc.RedirectPolicy{SameHost: true}))
+	if err == nil || !strings.Contains(err.Error(), "httpc: Redirect to different host is not allowed") {
+		t.Errorf("unexpected error: %v", err)
+	}
+
+	// Assert same host with different port
+	_, _, err = rc.DoRequest(context.Background(), "GET", "/other-port", httpc.Redirect(httpc.RedirectPolicy{SameHost: true}))
+	if err == nil || !strings.Contains(err.Error(), "httpc: Redirect to different host is not allowed") {
+		t.Errorf("unexpected error: %v", err)
+	}
+
+	// Assert allowed hosts
+	_, _, err = rc.DoRequest(context.Background(), "GET", "/other",
+		httpc.Redirect(httpc.RedirectPolicy{AllowedHosts: []string{"api.nbs.dev"}}))
+	if err == nil || !strings.Contains(err.Error(), "httpc: Redirect host is not in allowed list") {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
+func TestRedirectSensitiveHeaders(t *testing.T) {
+	srv, other := newRedirectServers()
+	defer srv.Close()
+	defer other.Close()
+	rc := httpc.MustNewClient(srv.URL, httpc.ClientBearerToken("token"))
+
+	// Assert Authorization header is dropped by default
+	_, body, err := rc.DoRequest(context.Background(), "GET", "/other")
+	if err != nil {
+		t.Errorf("unexpected error: %s", err)
+		return
+	}
+	if len(body) != 0 {
+		t.Errorf("unexpected condition: Authorization header is forwarded. Value = %s", body)
+	}
+
+	// Assert Authorization header is forwarded
+	_, body, err = rc.DoRequest(context.Background(), "GET", "/other",
+		httpc.Redirect(httpc.RedirectPolicy{ForwardSensitiveHeaders: true}))
+	if err != nil {
+		t.Errorf("unexpected error: %s", err)
+		return
+	}
+	if string(body) != "Bearer token" {
+		t.Errorf("unexpected condition: Authorization header is not forwarded. Value = %s", body)
+	}
+}
+
+func TestRedirectSensitiveHeadersCookie(t *testing.T) {
+	srv, other := newRedirectServers()
+	defer srv.Close()
+	defer other.Close()
+	jar := httpc.NewCookieJar()
+	u, _ := url.Parse(srv.URL)
+	jar.SetCookies(u, []*http.Cookie{{Name: "session", Value: "s3cret"}})
+	rc := httpc.MustNewClient(srv.URL, httpc.ClientBearerToken("token"), httpc.CookieJar(jar))
+
+	// Assert cookies of the original host are not forwarded
+	_, body, err := rc.DoRequest(context.Background(), "GET", "/other",
+		httpc.Redirect(httpc.RedirectPolicy{ForwardSensitiveHeaders: true}))
+	if err != nil {
+		t.Errorf("unexpected error: %s", err)
+		return
+	}
+	if string(body) != "Bearer token" {
+		t.Errorf("unexpected forwarded headers: %s", body)
+	}
+}
+
+func TestRedirectDowngrade(t *testing.T) {
+	_, other := newRedirectServers()
+	defer other.Close()
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// Redirect to the same host name over plain http
+		http.Redirect(w, r, other.URL+"/", http.StatusTemporaryRedirect)
+	}))
+	defer srv.Close()
+	rc := httpc.MustNewClient(srv.URL, httpc.Transport(srv.Client().Transport), httpc.ClientBearerToken("token"))
+
+	// Assert Authorization header is not forwarded to plain http
+	_, body, err := rc.DoRequest(context.Background(), "GET", "/",
+		httpc.Redirect(httpc.RedirectPolicy{ForwardSensitiveHeaders: true}))
+	if err != nil {
+		t.Errorf("unexpected error: %s", err)
+		return
+	}
+	if len(body) != 0 {
+		t.Errorf("unexpected condition: Authorization header is forwarded. Value = %s", body)
+	}
+
+	// Assert downgrade is not allowed if hosts are restricted
+	_, _, err = rc.DoRequest(context.Background(), "GET", "/",
+		httpc.Redirect(httpc.RedirectPolicy{AllowedHosts: []string{"api.nbs.dev"}}))
+	if err == nil || !strings.Contains(err.Error(), "httpc: Redirect from https to http is not allowed") {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
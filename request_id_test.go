@@ -0,0 +1,67 @@
+package httpc_test
+
+import (
+	"context"
+	"github.com/nbs-go/httpc"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestPropagateRequestId(t *testing.T) {
+	var received []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id := r.Header.Get(httpc.HeaderRequestId)
+		received = append(received, id)
+		w.Header().Set(httpc.HeaderRequestId, "upstream-"+id)
+	}))
+	defer srv.Close()
+	rc := httpc.MustNewClient(srv.URL, httpc.PropagateRequestId(""))
+
+	ctx := context.WithValue(context.Background(), httpc.ContextRequestId, "req-1")
+	resp, _, err := rc.DoRequest(ctx, "GET", "/")
+	if err != nil {
+		t.Errorf("unexpected error: %s", err)
+		return
+	}
+	if received[0] != "req-1" || httpc.RequestId(resp) != "req-1" || httpc.EchoedRequestId(resp) != "upstream-req-1" {
+		t.Errorf("unexpected request id. Received = %s, RequestId = %s, Echoed = %s", received[0],
+			httpc.RequestId(resp), httpc.EchoedRequestId(resp))
+	}
+
+	// Generated request id
+	resp, _, err = rc.DoRequest(context.Background(), "GET", "/")
+	if err != nil {
+		t.Errorf("unexpected error: %s", err)
+		return
+	}
+	if received[1] == "" || httpc.RequestId(resp) != received[1] {
+		t.Errorf("unexpected generated request id. Received = %s, RequestId = %s", received[1], httpc.RequestId(resp))
+	}
+
+	// Plain int key must not collide with ContextRequestId
+	ctx = context.WithValue(context.Background(), 1, "plain-key")
+	resp, _, _ = rc.DoRequest(ctx, "GET", "/")
+	if httpc.RequestId(resp) == "plain-key" {
+		t.Errorf("unexpected condition: plain int context key is used as request id")
+	}
+}
+
+func TestPropagateRequestIdCustomHeader(t *testing.T) {
+	var received string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received = r.Header.Get("X-Correlation-Id")
+	}))
+	defer srv.Close()
+	rc := httpc.MustNewClient(srv.URL, httpc.PropagateRequestId("X-Correlation-Id"))
+
+	ctx := context.WithValue(context.Background(), httpc.ContextRequestId, "req-1")
+	resp, _, err := rc.DoRequest(ctx, "GET", "/")
+	if err != nil {
+		t.Errorf("unexpected error: %s", err)
+		return
+	}
+	if received != "req-1" || httpc.EchoedRequestId(resp) != "" {
+		t.Errorf("unexpected request id. Received = %s, Echoed = %s", received, httpc.EchoedRequestId(resp))
+	}
+}
@@ -0,0 +1,147 @@
+package httpc
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"github.com/nbs-go/nlogger/v2/level"
+	logOption "github.com/nbs-go/nlogger/v2/option"
+	"io"
+	"net"
+	"time"
+)
+
+// Request log metadata keys
+const (
+	LogKeyRequestId    = "requestId"
+	LogKeyMethod       = "method"
+	LogKeyEndpoint     = "endpoint"
+	LogKeyHost         = "host"
+	LogKeyStatusCode   = "statusCode"
+	LogKeyDurationMs   = "durationMs"
+	LogKeyRequestSize  = "requestSize"
+	LogKeyResponseSize = "responseSize"
+	LogKeyAttempt      = "attempt"
+	LogKeyErrorClass   = "errorClass"
+	// LogKeyEchoedRequestId is set if PropagateRequestId option is set and upstream echoes request id
+	LogKeyEchoedRequestId = "echoedRequestId"
+	// Timing keys are set if TraceTimings option is set. Durations are in milliseconds with fraction
+	LogKeyDNSMs      = "dnsMs"
+	LogKeyConnectMs  = "connectMs"
+	LogKeyTLSMs      = "tlsMs"
+	LogKeyTTFBMs     = "ttfbMs"
+	LogKeyTransferMs = "transferMs"
+	LogKeyConnReused = "connReused"
+)
+
+// Error classes of failed request
+const (
+	ErrorClassTimeout    = "timeout"
+	ErrorClassCanceled   = "canceled"
+	ErrorClassDNS        = "dns"
+	ErrorClassTLS        = "tls"
+	ErrorClassConnection = "connection"
+	ErrorClassUnknown    = "unknown"
+)
+
+// requestLog holds fields of request completion log
+type requestLog struct {
+	reqId        string
+	method       string
+	endpoint     string
+	host         string
+	statusCode   int
+	startedAt    time.Time
+	requestSize  int
+	responseSize int
+	attempt      int
+	timings      *Timings
+	echoedReqId  string
+}
+
+func (l *requestLog) metadata() map[string]interface{} {
+	m := map[string]interface{}{
+		LogKeyRequestId:   l.reqId,
+		LogKeyMethod:      l.method,
+		LogKeyEndpoint:    l.endpoint,
+		LogKeyHost:        l.host,
+		LogKeyDurationMs:  time.Since(l.startedAt).Milliseconds(),
+		LogKeyRequestSize: l.requestSize,
+		LogKeyAttempt:     l.attempt,
+	}
+	if l.statusCode > 0 {
+		m[LogKeyStatusCode] = l.statusCode
+		m[LogKeyResponseSize] = l.responseSize
+	}
+	if l.echoedReqId != "" {
+		m[LogKeyEchoedRequestId] = l.echoedReqId
+	}
+	if t := l.timings; t != nil {
+		m[LogKeyDNSMs] = milliseconds(t.DNSLookup)
+		m[LogKeyConnectMs] = milliseconds(t.Connect)
+		m[LogKeyTLSMs] = milliseconds(t.TLSHandshake)
+		m[LogKeyTTFBMs] = milliseconds(t.TimeToFirstByte)
+		m[LogKeyTransferMs] = milliseconds(t.ContentTransfer)
+		m[LogKeyConnReused] = t.ConnReused
+	}
+	return m
+}
+
+// logCompleted writes request completion log with level based on response status class
+func (c *Client) logCompleted(ctx context.Context, l *requestLog) {
+	lvl, ok := c.statusLogLevel[l.statusCode/100]
+	if !ok {
+		lvl = level.Debug
+	}
+	c.logAt(lvl, "HTTP Request completed", logOption.Metadata(l.metadata()), logOption.Context(ctx))
+}
+
+// logFailed writes error log of request that failed to get a response
+func (c *Client) logFailed(ctx context.Context, l *requestLog, err error) {
+	m := l.metadata()
+	m[LogKeyErrorClass] = errorClass(err)
+	c.log.Error("HTTP Request failed", logOption.Metadata(m), logOption.Error(err), logOption.Context(ctx))
+}
+
+func milliseconds(d time.Duration) float64 {
+	return float64(d) / float64(time.Millisecond)
+}
+
+func (c *Client) logAt(lvl level.LogLevel, msg string, options ...logOption.SetterFunc) {
+	switch lvl {
+	case level.Fatal, level.Error:
+		c.log.Error(msg, options...)
+	case level.Warn:
+		c.log.Warn(msg, options...)
+	case level.Info:
+		c.log.Info(msg, options...)
+	default:
+		c.log.Debug(msg, options...)
+	}
+}
+
+// errorClass classifies error returned by http.Client
+func errorClass(err error) string {
+	var netErr net.Error
+	var dnsErr *net.DNSError
+	var opErr *net.OpError
+	var recordErr tls.RecordHeaderError
+	var authorityErr x509.UnknownAuthorityError
+	var hostnameErr x509.HostnameError
+	var invalidErr x509.CertificateInvalidError
+	switch {
+	case errors.Is(err, context.Canceled):
+		return ErrorClassCanceled
+	case errors.Is(err, context.DeadlineExceeded), errors.As(err, &netErr) && netErr.Timeout():
+		return ErrorClassTimeout
+	case errors.As(err, &dnsErr):
+		return ErrorClassDNS
+	case errors.As(err, &recordErr), errors.As(err, &authorityErr), errors.As(err, &hostnameErr),
+		errors.As(err, &invalidErr):
+		return ErrorClassTLS
+	case errors.As(err, &opErr), errors.Is(err, io.EOF), errors.Is(err, io.ErrUnexpectedEOF):
+		return ErrorClassConnection
+	}
+	return ErrorClassUnknown
+}
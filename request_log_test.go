@@ -0,0 +1,83 @@
+package httpc_test
+
+import (
+	"context"
+	"github.com/nbs-go/httpc"
+	"github.com/nbs-go/nlogger/v2/level"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestRequestLogMetadata(t *testing.T) {
+	l, restore := newCaptureLogger()
+	defer restore()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/users/missing" {
+			w.WriteHeader(http.StatusNotFound)
+		}
+		_, _ = w.Write([]byte("hello"))
+	}))
+	defer srv.Close()
+	rc := httpc.MustNewClient(srv.URL, httpc.StatusLogLevel(4, level.Warn))
+
+	for _, id := range []string{"1", "missing"} {
+		_, _, err := rc.DoRequest(context.Background(), "POST", "/users/{id}", httpc.PathParam("id", id),
+			httpc.SetBody([]byte("abc")))
+		if err != nil {
+			t.Errorf("unexpected error: %s", err)
+			return
+		}
+	}
+	entries := l.Entries("HTTP Request completed")
+	if len(entries) != 2 {
+		t.Errorf("unexpected log count: %d", len(entries))
+		return
+	}
+	m := entries[0].metadata
+	expected := map[string]interface{}{
+		httpc.LogKeyMethod:       "POST",
+		httpc.LogKeyEndpoint:     "/users/{id}",
+		httpc.LogKeyHost:         srv.Listener.Addr().String(),
+		httpc.LogKeyStatusCode:   200,
+		httpc.LogKeyRequestSize:  3,
+		httpc.LogKeyResponseSize: 5,
+		httpc.LogKeyAttempt:      1,
+	}
+	for k, v := range expected {
+		if m[k] != v {
+			t.Errorf("unexpected metadata %s. Expected = %v, Actual = %v", k, v, m[k])
+		}
+	}
+	if _, ok := m[httpc.LogKeyDurationMs].(int64); !ok {
+		t.Errorf("unexpected duration type: %T", m[httpc.LogKeyDurationMs])
+	}
+	if entries[0].level != "DEBUG" || entries[1].level != "WARN" {
+		t.Errorf("unexpected log levels. 2xx = %s, 4xx = %s", entries[0].level, entries[1].level)
+	}
+}
+
+func TestRequestLogErrorClass(t *testing.T) {
+	l, restore := newCaptureLogger()
+	defer restore()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(200 * time.Millisecond)
+	}))
+	defer srv.Close()
+	rc := httpc.MustNewClient(srv.URL)
+
+	_, _, err := rc.DoRequest(context.Background(), "GET", "/slow", httpc.Timeout(50))
+	if err == nil {
+		t.Errorf("expected error is not returned")
+		return
+	}
+	entries := l.Entries("HTTP Request failed")
+	if len(entries) != 1 {
+		t.Errorf("unexpected log count: %d", len(entries))
+		return
+	}
+	if entries[0].level != "ERROR" || entries[0].metadata[httpc.LogKeyErrorClass] != httpc.ErrorClassTimeout {
+		t.Errorf("unexpected log. Level = %s, Metadata = %v", entries[0].level, entries[0].metadata)
+	}
+}
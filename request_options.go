@@ -4,12 +4,14 @@ import (
 	"errors"
 	"net/http"
 	"net/url"
+	"strings"
 )
 
 type SetRequestOptionFn func(o *requestOptions)
 
 type PreRequestFn func(req *http.Request, reqBody []byte)
 
+// AddHeader adds key-value pairs to request header. Values are appended to existing values with the same key
 func AddHeader(args ...string) SetRequestOptionFn {
 	return func(o *requestOptions) {
 		// Panic args length is 0 or odd
@@ -19,7 +21,40 @@ func AddHeader(args ...string) SetRequestOptionFn {
 		}
 		// Apply headers
 		for i := 0; i < count; i += 2 {
-			o.header[args[i]] = args[i+1]
+			o.header[args[i]] = append(o.header[args[i]], args[i+1])
+		}
+	}
+}
+
+// SetHeader sets key-value pairs to request header. Values replace existing values with the same key
+func SetHeader(args ...string) SetRequestOptionFn {
+	return func(o *requestOptions) {
+		// Panic args length is 0 or odd
+		count := len(args)
+		if count == 0 || count%2 == 1 {
+			panic(errors.New("httpc: Invalid SetHeader() args count must >= 2 and even"))
+		}
+		// Apply headers
+		for i := 0; i < count; i += 2 {
+			setHeader(o.header, args[i], args[i+1])
+		}
+	}
+}
+
+// DelHeader deletes values of keys from request header
+func DelHeader(keys ...string) SetRequestOptionFn {
+	return func(o *requestOptions) {
+		for _, k := range keys {
+			delHeader(o.header, k)
+		}
+	}
+}
+
+// PreserveHeaderCase preserves letter case of header keys instead of converting it to canonical format
+func PreserveHeaderCase(keys ...string) SetRequestOptionFn {
+	return func(o *requestOptions) {
+		for _, k := range keys {
+			o.preserveHeaderCase[k] = true
 		}
 	}
 }
@@ -54,7 +89,7 @@ func SetJsonBody(body interface{}) SetRequestOptionFn {
 			return
 		}
 		// Set options
-		o.header[HeaderContentType] = MimeTypeJson
+		setHeader(o.header, HeaderContentType, MimeTypeJson)
 		o.body = body
 	}
 }
@@ -77,34 +112,150 @@ func SetUrlEncodedFormBody(body url.Values) SetRequestOptionFn {
 			return
 		}
 		// Set options
-		o.header[HeaderContentType] = MimeTypeUrlEncodedForm
+		setHeader(o.header, HeaderContentType, MimeTypeUrlEncodedForm)
 		o.body = body
 	}
 }
 
+// PathParam set value of {name} placeholder in endpoint path. Value will be escaped using url.PathEscape
+func PathParam(name string, value string) SetRequestOptionFn {
+	return func(o *requestOptions) {
+		o.pathParams[name] = value
+	}
+}
+
+// AddCookie adds cookie to request, in addition to cookies from Client cookie jar
+func AddCookie(c *http.Cookie) SetRequestOptionFn {
+	return func(o *requestOptions) {
+		o.cookies = append(o.cookies, c)
+	}
+}
+
+// Redirect set redirect policy for request. It overrides Client default redirect policy
+func Redirect(p RedirectPolicy) SetRequestOptionFn {
+	return func(o *requestOptions) {
+		o.redirectPolicy = &p
+	}
+}
+
+// DisableRedirect stops following redirects, the redirect response will be returned
+func DisableRedirect() SetRequestOptionFn {
+	return Redirect(RedirectPolicy{Disabled: true})
+}
+
+// Dump set log dump level, body limits and conditions for request. It overrides Client default log dump config
+func Dump(cfg DumpConfig) SetRequestOptionFn {
+	return func(o *requestOptions) {
+		o.dump = &cfg
+	}
+}
+
+// DisableDump disables log dump for request, e.g. for request with large or sensitive payload
+func DisableDump() SetRequestOptionFn {
+	return Dump(DumpConfig{Level: DumpOff})
+}
+
+// TraceTimings records request duration per phase, such as DNS lookup, connect and TLS handshake. Use ResponseTimings
+// to retrieve timings of response
+func TraceTimings() SetRequestOptionFn {
+	return func(o *requestOptions) {
+		o.traceTimings = true
+	}
+}
+
+// Auth set AuthProvider to authenticate request. It overrides Client default AuthProvider
+func Auth(p AuthProvider) SetRequestOptionFn {
+	return func(o *requestOptions) {
+		o.auth = p
+	}
+}
+
+// BasicAuth set HTTP Basic authentication to request
+func BasicAuth(username string, password string) SetRequestOptionFn {
+	return Auth(NewBasicAuth(username, password))
+}
+
+// BearerToken set static Bearer token to request
+func BearerToken(token string) SetRequestOptionFn {
+	return Auth(NewBearerAuth(token))
+}
+
+// TokenAuth set TokenSource to retrieve token for request. To reuse token across requests, pass a CachedTokenSource
+func TokenAuth(src TokenSource) SetRequestOptionFn {
+	return Auth(NewTokenAuth(src))
+}
+
+// DisableCanonicalHeader preserves letter case of all header keys. To preserve specific keys, use PreserveHeaderCase
 func DisableCanonicalHeader() SetRequestOptionFn {
 	return func(o *requestOptions) {
 		o.canonicalHeader = false
 	}
 }
 
+// setQuery set query parameters and replace existing values with the same key. If reset is true, existing query
+// parameters will be discarded
+func setQuery(q url.Values, reset bool) SetRequestOptionFn {
+	return func(o *requestOptions) {
+		if reset {
+			o.query = make(url.Values)
+		}
+		for k, v := range q {
+			o.query[k] = v
+		}
+	}
+}
+
+// setHeader replace values of key in header. Existing keys are matched case-insensitively, since keys are stored as is
+func setHeader(h http.Header, key string, value string) {
+	delHeader(h, key)
+	h[key] = []string{value}
+}
+
+// delHeader delete values of key in header. Keys are matched case-insensitively
+func delHeader(h http.Header, key string) {
+	for k := range h {
+		if strings.EqualFold(k, key) {
+			delete(h, k)
+		}
+	}
+}
+
+// getHeader returns first value of key in header. Keys are matched case-insensitively
+func getHeader(h http.Header, key string) string {
+	for k, v := range h {
+		if strings.EqualFold(k, key) && len(v) > 0 {
+			return v[0]
+		}
+	}
+	return ""
+}
+
 type requestOptions struct {
-	canonicalHeader bool
-	header          map[string]string
-	query           url.Values
-	body            interface{}
-	timeout         int
-	preRequest      PreRequestFn
+	canonicalHeader    bool
+	preserveHeaderCase map[string]bool
+	header             http.Header
+	query              url.Values
+	body               interface{}
+	timeout            int
+	preRequest         PreRequestFn
+	pathParams         map[string]string
+	auth               AuthProvider
+	cookies            []*http.Cookie
+	redirectPolicy     *RedirectPolicy
+	dump               *DumpConfig
+	traceTimings       bool
 }
 
 // evaluateClientOptions evaluates Client options and override default value
 func evaluateRequestOptions(args []SetRequestOptionFn) *requestOptions {
 	b := requestOptions{
-		canonicalHeader: true,
-		header:          make(map[string]string),
-		query:           make(url.Values),
-		body:            nil,
-		timeout:         10000, // Set default timeout to 10 second
+		canonicalHeader:    true,
+		preserveHeaderCase: make(map[string]bool),
+		header:             make(http.Header),
+		query:              make(url.Values),
+		body:               nil,
+		timeout:            10000, // Set default timeout to 10 second
+		pathParams:         make(map[string]string),
 	}
 	for _, fn := range args {
 		fn(&b)
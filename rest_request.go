@@ -39,25 +39,62 @@ func (rr *RESTRequest) AddHeader(args ...string) *RESTRequest {
 	return rr
 }
 
+func (rr *RESTRequest) SetHeader(args ...string) *RESTRequest {
+	rr.args = append(rr.args, SetHeader(args...))
+	return rr
+}
+
+func (rr *RESTRequest) DelHeader(keys ...string) *RESTRequest {
+	rr.args = append(rr.args, DelHeader(keys...))
+	return rr
+}
+
 func (rr *RESTRequest) AddQuery(args ...string) *RESTRequest {
 	rr.args = append(rr.args, AddQuery(args...))
 	return rr
 }
 
+func (rr *RESTRequest) PathParam(name string, value string) *RESTRequest {
+	rr.args = append(rr.args, PathParam(name, value))
+	return rr
+}
+
 func (rr *RESTRequest) Body(b interface{}) *RESTRequest {
 	rr.args = append(rr.args, SetJsonBody(b))
 	return rr
 }
 
+func (rr *RESTRequest) AddCookie(c *http.Cookie) *RESTRequest {
+	rr.args = append(rr.args, AddCookie(c))
+	return rr
+}
+
+func (rr *RESTRequest) BasicAuth(username string, password string) *RESTRequest {
+	rr.args = append(rr.args, BasicAuth(username, password))
+	return rr
+}
+
+func (rr *RESTRequest) BearerToken(token string) *RESTRequest {
+	rr.args = append(rr.args, BearerToken(token))
+	return rr
+}
+
 func (rr *RESTRequest) PreRequest(fn PreRequestFn) *RESTRequest {
 	rr.args = append(rr.args, PreRequest(fn))
 	return rr
 }
 
+// Paginate creates a Paginator that iterate pages of REST request using the given strategy
+func (rr *RESTRequest) Paginate(strategy PageStrategy) *Paginator {
+	args := append(make([]SetRequestOptionFn, 0, len(rr.args)+1), rr.args...)
+	args = append(args, SetHeader("Accept", MimeTypeJson))
+	return NewPaginator(rr.client, rr.method, rr.endpointPath, strategy, args...)
+}
+
 // Do prepare REST request, do and parse response body to JSON dst
 func (rr *RESTRequest) Do(ctx context.Context, dst interface{}) (*http.Response, error) {
 	// Set "accept" header to Json mime type
-	rr.AddHeader("Accept", MimeTypeJson)
+	rr.SetHeader("Accept", MimeTypeJson)
 	// Set request id in context
 	ctx = context.WithValue(ctx, ContextRequestId, rr.Id)
 	// Do request
@@ -76,3 +113,12 @@ func (rr *RESTRequest) Do(ctx context.Context, dst interface{}) (*http.Response,
 	}
 	return resp, nil
 }
+
+// Execute prepare REST request and do. Unlike Do, response body is not parsed and returned as is in Result
+func (rr *RESTRequest) Execute(ctx context.Context) (*Result, error) {
+	// Set "accept" header to Json mime type
+	rr.SetHeader("Accept", MimeTypeJson)
+	// Set request id in context
+	ctx = context.WithValue(ctx, ContextRequestId, rr.Id)
+	return rr.client.Execute(ctx, NewRequest(rr.method, rr.endpointPath, rr.args...))
+}
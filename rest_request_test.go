@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"github.com/nbs-go/httpc"
 	"net/http"
+	"net/http/httptest"
 	"net/url"
 	"testing"
 )
@@ -162,7 +163,7 @@ func TestRestUrlEncodedForm(t *testing.T) {
 }
 
 func TestRestPreRequest(t *testing.T) {
-	dc := httpc.NewClient("https://httpbin.nbs.dev", httpc.Namespace("httpc_dump"), httpc.LogDump(true))
+	dc := httpc.MustNewClient("https://httpbin.nbs.dev", httpc.Namespace("httpc_dump"), httpc.LogDump(true))
 	req := httpc.NewRESTRequest(dc, "GET", "/anything").
 		PreRequest(func(r *http.Request, rb []byte) {
 			// Add header
@@ -190,7 +191,7 @@ func TestRestPreRequest(t *testing.T) {
 }
 
 func TestRestUpperCaseHeader(t *testing.T) {
-	dc := httpc.NewClient("https://echo-api.nbs.dev", httpc.Namespace("echo-api"), httpc.LogDump(true))
+	dc := httpc.MustNewClient("https://echo-api.nbs.dev", httpc.Namespace("echo-api"), httpc.LogDump(true))
 	req := httpc.NewRESTRequest(dc, "GET", "/", httpc.DisableCanonicalHeader()).
 		AddHeader("MESSAGE", "hello")
 	// Do request
@@ -206,3 +207,84 @@ func TestRestUpperCaseHeader(t *testing.T) {
 		t.Errorf("unexpected value: %s", actual)
 	}
 }
+
+func TestRestPathParam(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode(HttpBinResult{Url: r.URL.EscapedPath()})
+	}))
+	defer srv.Close()
+	pc := httpc.MustNewClient(srv.URL)
+
+	req := httpc.NewRESTRequest(pc, "GET", "/users/{id}/files/{name}").
+		PathParam("id", "42").
+		PathParam("name", "../etc/passwd?x=1")
+	var respBody HttpBinResult
+	_, err := req.Do(context.Background(), &respBody)
+	if err != nil {
+		t.Errorf("unexpected error: %s", err)
+		return
+	}
+	expected := "/users/42/files/..%2Fetc%2Fpasswd%3Fx=1"
+	if respBody.Url != expected {
+		t.Errorf("unexpected actual value. Expected = %s, Actual = %s", expected, respBody.Url)
+	}
+}
+
+func TestRestUnresolvedPathParam(t *testing.T) {
+	req := httpc.NewRESTRequest(c, "GET", "/users/{id}")
+	_, err := req.Do(context.Background(), nil)
+	if err == nil || err.Error() != "httpc: Unresolved path parameter in endpoint path. Param = id, Path = /users/{id}" {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
+func TestRestExecute(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+		_, _ = w.Write([]byte(r.Header.Get("Accept") + " not found"))
+	}))
+	defer srv.Close()
+	pc := httpc.MustNewClient(srv.URL)
+
+	req := httpc.NewRESTRequest(pc, "GET", "/users/1")
+	result, err := req.Execute(context.Background())
+	if err != nil {
+		t.Errorf("unexpected error: %s", err)
+		return
+	}
+	if result.StatusCode() != http.StatusNotFound || result.Text() != "application/json not found" ||
+		result.RequestId != req.Id {
+		t.Errorf("unexpected result. Status = %d, Body = %s, RequestId = %s", result.StatusCode(), result.Body,
+			result.RequestId)
+	}
+}
+
+func TestRestPathParamDotSegment(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode(HttpBinResult{Url: r.URL.EscapedPath()})
+	}))
+	defer srv.Close()
+	pc := httpc.MustNewClient(srv.URL + "/v1")
+
+	for _, tmpl := range []string{"/users/{id}/profile", "/{id}/profile"} {
+		for _, v := range []string{".", ".."} {
+			_, err := httpc.NewRESTRequest(pc, "GET", tmpl).PathParam("id", v).Do(context.Background(), nil)
+			expected := "httpc: Invalid path parameter value, dot segment is not allowed. Param = id, Value = " + v
+			if err == nil || err.Error() != expected {
+				t.Errorf("unexpected error. Path = %s, Value = %s, Error = %v", tmpl, v, err)
+			}
+		}
+	}
+
+	var respBody HttpBinResult
+	_, err := httpc.NewRESTRequest(pc, "GET", "/users/{id}/profile").PathParam("id", "../x").
+		Do(context.Background(), &respBody)
+	if err != nil {
+		t.Errorf("unexpected error: %s", err)
+		return
+	}
+	expected := "/v1/users/..%2Fx/profile"
+	if respBody.Url != expected {
+		t.Errorf("unexpected actual value. Expected = %s, Actual = %s", expected, respBody.Url)
+	}
+}
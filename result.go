@@ -0,0 +1,102 @@
+package httpc
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// NewRequest creates Request to send with Client.Execute
+func NewRequest(method Method, endpointPath string, args ...SetRequestOptionFn) *Request {
+	return &Request{
+		Method:       method,
+		EndpointPath: endpointPath,
+		Options:      args,
+	}
+}
+
+// Request is a request to send with Client.Execute
+type Request struct {
+	Method       Method
+	EndpointPath string
+	Options      []SetRequestOptionFn
+}
+
+// AddOption adds request options
+func (r *Request) AddOption(fn ...SetRequestOptionFn) *Request {
+	r.Options = append(r.Options, fn...)
+	return r
+}
+
+// Result is the result of Client.Execute. Response body is already read and closed, use Body instead
+type Result struct {
+	Response *http.Response
+	Body     []byte
+	// RequestId is the request id from context, or generated if not set
+	RequestId string
+	// EchoedRequestId is the request id echoed by upstream, if PropagateRequestId option is set
+	EchoedRequestId string
+	// Duration is the total duration of request, including replayed attempts and reading response body
+	Duration time.Duration
+	// Attempts is the number of sent requests, e.g. 2 if request is replayed after authentication refreshed
+	Attempts int
+	// URL is the final url after redirects
+	URL *url.URL
+	// Decompressed is true if response body is transparently decompressed by http.Transport
+	Decompressed bool
+	// CacheStatus is the cache status reported by upstream in Cache-Status header as defined in RFC 9211,
+	// or X-Cache header
+	CacheStatus string
+	// Timings is request duration per phase, if TraceTimings option is set
+	Timings *Timings
+}
+
+func newResult(resp *http.Response, body []byte, l *requestLog) *Result {
+	cs := resp.Header.Get("Cache-Status")
+	if cs == "" {
+		cs = resp.Header.Get("X-Cache")
+	}
+	return &Result{
+		Response:        resp,
+		Body:            body,
+		RequestId:       l.reqId,
+		EchoedRequestId: l.echoedReqId,
+		Duration:        time.Since(l.startedAt),
+		Attempts:        l.attempt,
+		URL:             resp.Request.URL,
+		Decompressed:    resp.Uncompressed,
+		CacheStatus:     cs,
+		Timings:         l.timings,
+	}
+}
+
+// StatusCode returns response status code
+func (r *Result) StatusCode() int {
+	return r.Response.StatusCode
+}
+
+// IsSuccess returns true if response status is 2xx
+func (r *Result) IsSuccess() bool {
+	return r.Response.StatusCode >= 200 && r.Response.StatusCode < 300
+}
+
+// RedirectChain returns urls of followed redirects. See RedirectChain
+func (r *Result) RedirectChain() []*url.URL {
+	return RedirectChain(r.Response)
+}
+
+// JSON parse response body as JSON to dst
+func (r *Result) JSON(dst interface{}) error {
+	err := json.Unmarshal(r.Body, dst)
+	if err != nil {
+		return fmt.Errorf("httpc: Failed to parse response body as JSON. Error = %w", err)
+	}
+	return nil
+}
+
+// Text returns response body as string
+func (r *Result) Text() string {
+	return string(r.Body)
+}
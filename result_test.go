@@ -0,0 +1,56 @@
+package httpc_test
+
+import (
+	"compress/gzip"
+	"context"
+	"github.com/nbs-go/httpc"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestExecute(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/old" {
+			http.Redirect(w, r, "/new", http.StatusFound)
+			return
+		}
+		w.Header().Set(httpc.HeaderContentType, httpc.MimeTypeJson)
+		w.Header().Set("Content-Encoding", "gzip")
+		w.Header().Set("Cache-Status", "ExampleCache; hit")
+		gz := gzip.NewWriter(w)
+		_, _ = gz.Write([]byte(`{"name":"john"}`))
+		_ = gz.Close()
+	}))
+	defer srv.Close()
+	rc := httpc.MustNewClient(srv.URL)
+
+	ctx := context.WithValue(context.Background(), httpc.ContextRequestId, "req-1")
+	r, err := rc.Execute(ctx, httpc.NewRequest("GET", "/old"))
+	if err != nil {
+		t.Errorf("unexpected error: %s", err)
+		return
+	}
+	if !r.IsSuccess() || r.StatusCode() != http.StatusOK || r.RequestId != "req-1" || r.Attempts != 1 ||
+		r.URL.Path != "/new" || len(r.RedirectChain()) != 2 || !r.Decompressed ||
+		r.CacheStatus != "ExampleCache; hit" || r.Duration <= 0 {
+		t.Errorf("unexpected result: %+v", r)
+	}
+	var dst struct {
+		Name string `json:"name"`
+	}
+	if err = r.JSON(&dst); err != nil || dst.Name != "john" {
+		t.Errorf("unexpected JSON result. Error = %v, Name = %s", err, dst.Name)
+	}
+	if r.Text() != `{"name":"john"}` {
+		t.Errorf("unexpected text: %s", r.Text())
+	}
+}
+
+func TestExecuteNilRequest(t *testing.T) {
+	rc := httpc.MustNewClient("http://localhost")
+	_, err := rc.Execute(context.Background(), nil)
+	if err == nil {
+		t.Errorf("expected error is not returned")
+	}
+}
@@ -0,0 +1,321 @@
+package httpc
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// AWS Signature Version 4 constants
+const (
+	SigV4Algorithm      = "AWS4-HMAC-SHA256"
+	SigV4UnsignedBody   = "UNSIGNED-PAYLOAD"
+	sigV4TimeFormat     = "20060102T150405Z"
+	sigV4ShortTimeFmt   = "20060102"
+	sigV4ScopeTerminate = "aws4_request"
+)
+
+// AWS Signature Version 4 headers and query parameters
+const (
+	HeaderAmzDate          = "X-Amz-Date"
+	HeaderAmzSecurityToken = "X-Amz-Security-Token"
+	HeaderAmzContentSHA256 = "X-Amz-Content-Sha256"
+)
+
+// sigV4IgnoredHeaders are headers that are not signed, since they could be modified by proxies
+var sigV4IgnoredHeaders = map[string]bool{
+	"authorization":   true,
+	"user-agent":      true,
+	"x-amzn-trace-id": true,
+	"expect":          true,
+}
+
+// SigV4Credentials is AWS access key credentials
+type SigV4Credentials struct {
+	AccessKeyId     string
+	SecretAccessKey string
+	// SessionToken is the temporary credentials session token. Optional
+	SessionToken string
+}
+
+// SigV4CredentialsFn retrieve credentials before signing, e.g. to rotate temporary credentials
+type SigV4CredentialsFn func(ctx context.Context) (*SigV4Credentials, error)
+
+type SetSigV4OptionFn func(o *sigV4Options)
+
+type sigV4Options struct {
+	unsignedPayload        bool
+	disableURIPathEscaping bool
+	contentSHA256Header    bool
+	s3                     bool
+	credentialsFn          SigV4CredentialsFn
+	now                    func() time.Time
+}
+
+// SigV4UnsignedPayload use UNSIGNED-PAYLOAD instead of hashing request body, e.g. for streaming upload
+func SigV4UnsignedPayload() SetSigV4OptionFn {
+	return func(o *sigV4Options) {
+		o.unsignedPayload = true
+	}
+}
+
+// SigV4S3 configure signer for Amazon S3 compatible storage. S3 does not double-escape path, requires
+// X-Amz-Content-Sha256 header and presigned url with UNSIGNED-PAYLOAD
+func SigV4S3() SetSigV4OptionFn {
+	return func(o *sigV4Options) {
+		o.disableURIPathEscaping = true
+		o.contentSHA256Header = true
+		o.s3 = true
+	}
+}
+
+// SigV4CredentialsProvider set function to retrieve credentials before every signing. It overrides static credentials
+func SigV4CredentialsProvider(fn SigV4CredentialsFn) SetSigV4OptionFn {
+	return func(o *sigV4Options) {
+		o.credentialsFn = fn
+	}
+}
+
+// SigV4Clock override signing time. Intended for deterministic tests
+func SigV4Clock(now func() time.Time) SetSigV4OptionFn {
+	return func(o *sigV4Options) {
+		o.now = now
+	}
+}
+
+// NewSigV4Signer creates AuthProvider that sign request using AWS Signature Version 4
+func NewSigV4Signer(cred SigV4Credentials, region string, service string, args ...SetSigV4OptionFn) *SigV4Signer {
+	o := sigV4Options{
+		now: time.Now,
+	}
+	for _, fn := range args {
+		fn(&o)
+	}
+	return &SigV4Signer{
+		cred:    cred,
+		region:  region,
+		service: service,
+		o:       &o,
+	}
+}
+
+type SigV4Signer struct {
+	cred    SigV4Credentials
+	region  string
+	service string
+	o       *sigV4Options
+}
+
+// Authenticate sign request and set signature in Authorization header
+func (s *SigV4Signer) Authenticate(ctx context.Context, req *http.Request, body []byte) error {
+	cred, err := s.credentials(ctx)
+	if err != nil {
+		return err
+	}
+	t := s.o.now().UTC()
+	payloadHash := s.payloadHash(body)
+	// Set headers to be signed
+	req.Header.Del(HeaderAuthorization)
+	req.Header.Set(HeaderAmzDate, t.Format(sigV4TimeFormat))
+	if cred.SessionToken != "" {
+		req.Header.Set(HeaderAmzSecurityToken, cred.SessionToken)
+	}
+	if s.o.contentSHA256Header {
+		req.Header.Set(HeaderAmzContentSHA256, payloadHash)
+	}
+	// Sign
+	signedHeaders, canonicalHeaders := sigV4CanonicalHeaders(req)
+	cr := s.canonicalRequest(req, req.URL.RawQuery, canonicalHeaders, signedHeaders, payloadHash)
+	scope := s.scope(t)
+	sig := s.signature(cred, t, scope, cr)
+	req.Header.Set(HeaderAuthorization, fmt.Sprintf("%s Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		SigV4Algorithm, cred.AccessKeyId, scope, signedHeaders, sig))
+	return nil
+}
+
+// Presign returns presigned url of request that is valid until expires. Request headers will be signed, so client
+// must send the same headers. If signer is configured for S3, payload is not signed
+func (s *SigV4Signer) Presign(ctx context.Context, req *http.Request, body []byte, expires time.Duration) (*url.URL, error) {
+	if expires <= 0 || expires > 7*24*time.Hour {
+		return nil, errors.New("httpc: Presigned url expiry must be greater than 0 and at most 7 days")
+	}
+	cred, err := s.credentials(ctx)
+	if err != nil {
+		return nil, err
+	}
+	t := s.o.now().UTC()
+	scope := s.scope(t)
+	signedHeaders, canonicalHeaders := sigV4CanonicalHeaders(req)
+	// Set signing query parameters
+	q := req.URL.Query()
+	q.Set("X-Amz-Algorithm", SigV4Algorithm)
+	q.Set("X-Amz-Credential", cred.AccessKeyId+"/"+scope)
+	q.Set("X-Amz-Date", t.Format(sigV4TimeFormat))
+	q.Set("X-Amz-Expires", strconv.FormatInt(int64(expires/time.Second), 10))
+	q.Set("X-Amz-SignedHeaders", signedHeaders)
+	if cred.SessionToken != "" {
+		q.Set(HeaderAmzSecurityToken, cred.SessionToken)
+	}
+	rawQuery := sigV4CanonicalQuery(q)
+	// Sign. S3 presigned url does not include payload, since it is unknown when url is created
+	payloadHash := s.payloadHash(body)
+	if s.o.s3 {
+		payloadHash = SigV4UnsignedBody
+	}
+	cr := s.canonicalRequest(req, rawQuery, canonicalHeaders, signedHeaders, payloadHash)
+	sig := s.signature(cred, t, scope, cr)
+	u := *req.URL
+	u.RawQuery = rawQuery + "&X-Amz-Signature=" + sig
+	return &u, nil
+}
+
+func (s *SigV4Signer) credentials(ctx context.Context) (*SigV4Credentials, error) {
+	if s.o.credentialsFn == nil {
+		return &s.cred, nil
+	}
+	cred, err := s.o.credentialsFn(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("httpc: Failed to retrieve SigV4 credentials. Error = %w", err)
+	}
+	return cred, nil
+}
+
+func (s *SigV4Signer) payloadHash(body []byte) string {
+	if s.o.unsignedPayload {
+		return SigV4UnsignedBody
+	}
+	h := sha256.Sum256(body)
+	return hex.EncodeToString(h[:])
+}
+
+func (s *SigV4Signer) scope(t time.Time) string {
+	return strings.Join([]string{t.Format(sigV4ShortTimeFmt), s.region, s.service, sigV4ScopeTerminate}, "/")
+}
+
+func (s *SigV4Signer) canonicalRequest(req *http.Request, rawQuery string, canonicalHeaders string,
+	signedHeaders string, payloadHash string) string {
+	// Compose canonical uri
+	uri := req.URL.EscapedPath()
+	if req.URL.Opaque != "" {
+		uri = req.URL.Opaque
+	}
+	if uri == "" {
+		uri = "/"
+	}
+	if !s.o.disableURIPathEscaping {
+		uri = sigV4Escape(uri, false)
+	}
+	// Compose canonical query, url could be presigned
+	q, _ := url.ParseQuery(rawQuery)
+	return strings.Join([]string{
+		req.Method,
+		uri,
+		sigV4CanonicalQuery(q),
+		canonicalHeaders,
+		signedHeaders,
+		payloadHash,
+	}, "\n")
+}
+
+func (s *SigV4Signer) signature(cred *SigV4Credentials, t time.Time, scope string, canonicalRequest string) string {
+	crHash := sha256.Sum256([]byte(canonicalRequest))
+	sts := strings.Join([]string{
+		SigV4Algorithm,
+		t.Format(sigV4TimeFormat),
+		scope,
+		hex.EncodeToString(crHash[:]),
+	}, "\n")
+	// Derive signing key
+	k := hmacSHA256([]byte("AWS4"+cred.SecretAccessKey), t.Format(sigV4ShortTimeFmt))
+	k = hmacSHA256(k, s.region)
+	k = hmacSHA256(k, s.service)
+	k = hmacSHA256(k, sigV4ScopeTerminate)
+	return hex.EncodeToString(hmacSHA256(k, sts))
+}
+
+// sigV4CanonicalHeaders returns signed headers list and canonical headers
+func sigV4CanonicalHeaders(req *http.Request) (string, string) {
+	headers := map[string][]string{}
+	host := req.Host
+	if host == "" {
+		host = req.URL.Host
+	}
+	headers["host"] = []string{host}
+	for k, v := range req.Header {
+		lk := strings.ToLower(k)
+		if sigV4IgnoredHeaders[lk] {
+			continue
+		}
+		headers[lk] = append(headers[lk], v...)
+	}
+	keys := make([]string, 0, len(headers))
+	for k := range headers {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	var b strings.Builder
+	for _, k := range keys {
+		values := make([]string, len(headers[k]))
+		for i, v := range headers[k] {
+			values[i] = strings.Join(strings.Fields(v), " ")
+		}
+		b.WriteString(k)
+		b.WriteByte(':')
+		b.WriteString(strings.Join(values, ","))
+		b.WriteByte('\n')
+	}
+	return strings.Join(keys, ";"), b.String()
+}
+
+// sigV4CanonicalQuery encode query sorted by key and value with RFC 3986 escaping
+func sigV4CanonicalQuery(q url.Values) string {
+	escaped := make(map[string][]string, len(q))
+	keys := make([]string, 0, len(q))
+	for k, values := range q {
+		ek := sigV4Escape(k, true)
+		keys = append(keys, ek)
+		for _, v := range values {
+			escaped[ek] = append(escaped[ek], sigV4Escape(v, true))
+		}
+	}
+	sort.Strings(keys)
+	pairs := make([]string, 0, len(keys))
+	for _, k := range keys {
+		values := escaped[k]
+		sort.Strings(values)
+		for _, v := range values {
+			pairs = append(pairs, k+"="+v)
+		}
+	}
+	return strings.Join(pairs, "&")
+}
+
+// sigV4Escape escape string with RFC 3986 unreserved characters. If encodeSlash is false, "/" is not escaped
+func sigV4Escape(s string, encodeSlash bool) string {
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if ('A' <= c && c <= 'Z') || ('a' <= c && c <= 'z') || ('0' <= c && c <= '9') ||
+			c == '-' || c == '_' || c == '.' || c == '~' || (c == '/' && !encodeSlash) {
+			b.WriteByte(c)
+			continue
+		}
+		_, _ = fmt.Fprintf(&b, "%%%02X", c)
+	}
+	return b.String()
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	h := hmac.New(sha256.New, key)
+	h.Write([]byte(data))
+	return h.Sum(nil)
+}
@@ -0,0 +1,135 @@
+package httpc
+
+import (
+	"context"
+	"crypto/tls"
+	"net/http"
+	"net/http/httptrace"
+	"sync"
+	"time"
+)
+
+// Timings is request duration per phase recorded when TraceTimings option is set. Phases that did not happen, e.g.
+// DNS lookup on reused connection, have zero duration. If redirects are followed, timings are of the last request
+type Timings struct {
+	// DNSLookup is duration of resolving host
+	DNSLookup time.Duration
+	// Connect is duration of establishing TCP connection
+	Connect time.Duration
+	// TLSHandshake is duration of TLS handshake
+	TLSHandshake time.Duration
+	// TimeToFirstByte is duration from request is written until first response byte is received
+	TimeToFirstByte time.Duration
+	// ContentTransfer is duration from first response byte is received until response body is read
+	ContentTransfer time.Duration
+	// Total is duration from request is started until response body is read
+	Total time.Duration
+	// ConnReused is true if request is sent using a connection from pool
+	ConnReused bool
+}
+
+type timingsKey struct{}
+
+// ResponseTimings returns timings of request that lead to the response, or nil if TraceTimings option is not set
+func ResponseTimings(resp *http.Response) *Timings {
+	if resp == nil || resp.Request == nil {
+		return nil
+	}
+	tt, ok := resp.Request.Context().Value(timingsKey{}).(*timingsTrace)
+	if !ok {
+		return nil
+	}
+	return tt.timings()
+}
+
+// timingsTrace records timestamps from httptrace.ClientTrace hooks
+type timingsTrace struct {
+	mu           sync.Mutex
+	start        time.Time
+	dnsStart     time.Time
+	dnsDone      time.Time
+	connectStart time.Time
+	connectDone  time.Time
+	tlsStart     time.Time
+	tlsDone      time.Time
+	wroteRequest time.Time
+	firstByte    time.Time
+	done         time.Time
+	connReused   bool
+}
+
+// withTimingsTrace returns context with httptrace.ClientTrace that records request timings. If expose is false,
+// timings are not retrievable with ResponseTimings
+func withTimingsTrace(ctx context.Context, expose bool) (context.Context, *timingsTrace) {
+	tt := timingsTrace{start: time.Now()}
+	set := func(t *time.Time) {
+		tt.mu.Lock()
+		*t = time.Now()
+		tt.mu.Unlock()
+	}
+	trace := httptrace.ClientTrace{
+		GetConn: func(string) {
+			// Reset timings, since hooks are called again on redirect
+			tt.mu.Lock()
+			tt.dnsStart, tt.dnsDone = time.Time{}, time.Time{}
+			tt.connectStart, tt.connectDone = time.Time{}, time.Time{}
+			tt.tlsStart, tt.tlsDone = time.Time{}, time.Time{}
+			tt.wroteRequest, tt.firstByte = time.Time{}, time.Time{}
+			tt.mu.Unlock()
+		},
+		GotConn: func(info httptrace.GotConnInfo) {
+			tt.mu.Lock()
+			tt.connReused = info.Reused
+			tt.mu.Unlock()
+		},
+		DNSStart: func(httptrace.DNSStartInfo) { set(&tt.dnsStart) },
+		DNSDone:  func(httptrace.DNSDoneInfo) { set(&tt.dnsDone) },
+		ConnectStart: func(string, string) {
+			// Keep the first attempt if multiple addresses are dialed
+			tt.mu.Lock()
+			if tt.connectStart.IsZero() {
+				tt.connectStart = time.Now()
+			}
+			tt.mu.Unlock()
+		},
+		ConnectDone:          func(string, string, error) { set(&tt.connectDone) },
+		TLSHandshakeStart:    func() { set(&tt.tlsStart) },
+		TLSHandshakeDone:     func(tls.ConnectionState, error) { set(&tt.tlsDone) },
+		WroteRequest:         func(httptrace.WroteRequestInfo) { set(&tt.wroteRequest) },
+		GotFirstResponseByte: func() { set(&tt.firstByte) },
+	}
+	ctx = httptrace.WithClientTrace(ctx, &trace)
+	if expose {
+		ctx = context.WithValue(ctx, timingsKey{}, &tt)
+	}
+	return ctx, &tt
+}
+
+// finish marks response body is read
+func (tt *timingsTrace) finish() {
+	tt.mu.Lock()
+	tt.done = time.Now()
+	tt.mu.Unlock()
+}
+
+func (tt *timingsTrace) timings() *Timings {
+	tt.mu.Lock()
+	defer tt.mu.Unlock()
+	return &Timings{
+		DNSLookup:       since(tt.dnsStart, tt.dnsDone),
+		Connect:         since(tt.connectStart, tt.connectDone),
+		TLSHandshake:    since(tt.tlsStart, tt.tlsDone),
+		TimeToFirstByte: since(tt.wroteRequest, tt.firstByte),
+		ContentTransfer: since(tt.firstByte, tt.done),
+		Total:           since(tt.start, tt.done),
+		ConnReused:      tt.connReused,
+	}
+}
+
+// since returns duration between start and end, or zero if any of them is not recorded
+func since(start time.Time, end time.Time) time.Duration {
+	if start.IsZero() || end.IsZero() {
+		return 0
+	}
+	return end.Sub(start)
+}
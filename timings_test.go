@@ -0,0 +1,55 @@
+package httpc_test
+
+import (
+	"context"
+	"github.com/nbs-go/httpc"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestTraceTimings(t *testing.T) {
+	l, restore := newCaptureLogger()
+	defer restore()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(20 * time.Millisecond)
+		_, _ = w.Write([]byte("hello"))
+	}))
+	defer srv.Close()
+	rc := httpc.MustNewClient(srv.URL)
+
+	resp, _, err := rc.DoRequest(context.Background(), "GET", "/", httpc.TraceTimings())
+	if err != nil {
+		t.Errorf("unexpected error: %s", err)
+		return
+	}
+	tm := httpc.ResponseTimings(resp)
+	if tm == nil {
+		t.Errorf("unexpected nil timings")
+		return
+	}
+	if tm.ConnReused || tm.Connect <= 0 || tm.TLSHandshake != 0 || tm.TimeToFirstByte < 20*time.Millisecond ||
+		tm.Total < tm.TimeToFirstByte {
+		t.Errorf("unexpected first request timings: %+v", tm)
+	}
+
+	resp, _, err = rc.DoRequest(context.Background(), "GET", "/", httpc.TraceTimings())
+	if err != nil {
+		t.Errorf("unexpected error: %s", err)
+		return
+	}
+	tm = httpc.ResponseTimings(resp)
+	if !tm.ConnReused || tm.Connect != 0 {
+		t.Errorf("unexpected reused connection timings: %+v", tm)
+	}
+	entries := l.Entries("HTTP Request completed")
+	if len(entries) != 2 || entries[1].metadata[httpc.LogKeyConnReused] != true {
+		t.Errorf("unexpected log entries: %v", entries)
+	}
+
+	resp, _, _ = rc.DoRequest(context.Background(), "GET", "/")
+	if httpc.ResponseTimings(resp) != nil {
+		t.Errorf("unexpected timings without TraceTimings option")
+	}
+}
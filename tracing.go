@@ -0,0 +1,183 @@
+package httpc
+
+import (
+	"context"
+	"encoding/hex"
+	"net/http"
+	"net/url"
+	"strconv"
+)
+
+// W3C Trace Context headers
+const (
+	HeaderTraceParent = "traceparent"
+	HeaderTraceState  = "tracestate"
+)
+
+// Span attribute keys as defined in OpenTelemetry HTTP semantic conventions
+const (
+	AttrHTTPRequestMethod    = "http.request.method"
+	AttrHTTPResponseStatus   = "http.response.status_code"
+	AttrHTTPResendCount      = "http.request.resend_count"
+	AttrHTTPRequestBodySize  = "http.request.body.size"
+	AttrHTTPResponseBodySize = "http.response.body.size"
+	AttrURLFull              = "url.full"
+	AttrURLTemplate          = "url.template"
+	AttrServerAddress        = "server.address"
+	AttrServerPort           = "server.port"
+	AttrErrorType            = "error.type"
+	AttrRequestId            = "httpc.request_id"
+)
+
+// SpanKind is the role of span in a trace
+type SpanKind int8
+
+const (
+	// SpanKindInternal is kind of span around a logical request, that may consist of multiple attempts
+	SpanKindInternal SpanKind = iota + 1
+	// SpanKindClient is kind of span around a single HTTP request attempt
+	SpanKindClient
+)
+
+// Attribute is a span attribute
+type Attribute struct {
+	Key   string
+	Value interface{}
+}
+
+// SpanContext identifies a span, it is propagated to outgoing request in W3C traceparent header
+type SpanContext struct {
+	TraceId    [16]byte
+	SpanId     [8]byte
+	Sampled    bool
+	TraceState string
+}
+
+// IsValid returns true if trace id and span id is not zero
+func (sc SpanContext) IsValid() bool {
+	return sc.TraceId != [16]byte{} && sc.SpanId != [8]byte{}
+}
+
+// TraceParent returns W3C traceparent header value
+func (sc SpanContext) TraceParent() string {
+	flags := "00"
+	if sc.Sampled {
+		flags = "01"
+	}
+	return "00-" + hex.EncodeToString(sc.TraceId[:]) + "-" + hex.EncodeToString(sc.SpanId[:]) + "-" + flags
+}
+
+// Tracer starts spans around each logical request and each of request attempts. Implementation must be safe for
+// concurrent use. See otel package for OpenTelemetry implementation
+type Tracer interface {
+	// Start starts a span as child of span in ctx and returns context that contains the new span
+	Start(ctx context.Context, name string, kind SpanKind, attrs ...Attribute) (context.Context, Span)
+}
+
+// Span is a traced operation
+type Span interface {
+	SetAttributes(attrs ...Attribute)
+	// RecordError records error and mark span as failed
+	RecordError(err error)
+	SpanContext() SpanContext
+	End()
+}
+
+// requestTrace holds spans of a logical request. Methods of nil requestTrace do nothing
+type requestTrace struct {
+	tracer  Tracer
+	name    string
+	span    Span
+	attempt Span
+}
+
+// startTrace starts span around logical request if Client tracer is set
+func (c *Client) startTrace(ctx context.Context, method Method, endpointPath string, u *url.URL, reqId string) (context.Context, *requestTrace) {
+	if c.tracer == nil {
+		return ctx, nil
+	}
+	rt := requestTrace{
+		tracer: c.tracer,
+		name:   method + " " + endpointPath,
+	}
+	attrs := []Attribute{
+		{Key: AttrHTTPRequestMethod, Value: method},
+		{Key: AttrURLFull, Value: c.redactor.redactUrl(u).String()},
+		{Key: AttrURLTemplate, Value: endpointPath},
+		{Key: AttrServerAddress, Value: u.Hostname()},
+		{Key: AttrRequestId, Value: reqId},
+	}
+	if port := u.Port(); port != "" {
+		p, _ := strconv.Atoi(port)
+		attrs = append(attrs, Attribute{Key: AttrServerPort, Value: p})
+	}
+	ctx, rt.span = c.tracer.Start(ctx, rt.name, SpanKindInternal, attrs...)
+	return ctx, &rt
+}
+
+// startAttempt starts span around request attempt
+func (rt *requestTrace) startAttempt(ctx context.Context, attempt int) context.Context {
+	if rt == nil {
+		return ctx
+	}
+	var attrs []Attribute
+	if attempt > 1 {
+		attrs = append(attrs, Attribute{Key: AttrHTTPResendCount, Value: attempt - 1})
+	}
+	ctx, rt.attempt = rt.tracer.Start(ctx, rt.name, SpanKindClient, attrs...)
+	return ctx
+}
+
+// inject propagates attempt span context to request header
+func (rt *requestTrace) inject(req *http.Request) {
+	if rt == nil {
+		return
+	}
+	sc := rt.attempt.SpanContext()
+	if !sc.IsValid() {
+		return
+	}
+	req.Header.Set(HeaderTraceParent, sc.TraceParent())
+	if sc.TraceState != "" {
+		req.Header.Set(HeaderTraceState, sc.TraceState)
+	}
+}
+
+// endAttempt ends attempt span with response status or error
+func (rt *requestTrace) endAttempt(resp *http.Response, err error) {
+	if rt == nil || rt.attempt == nil {
+		return
+	}
+	endSpan(rt.attempt, resp, err)
+	rt.attempt = nil
+}
+
+// end ends logical request span
+func (rt *requestTrace) end(resp *http.Response, l *requestLog, err error) {
+	if rt == nil {
+		return
+	}
+	rt.endAttempt(resp, err)
+	rt.span.SetAttributes(
+		Attribute{Key: AttrHTTPRequestBodySize, Value: l.requestSize},
+		Attribute{Key: AttrHTTPResendCount, Value: l.attempt - 1},
+	)
+	if err == nil && resp != nil {
+		rt.span.SetAttributes(Attribute{Key: AttrHTTPResponseBodySize, Value: l.responseSize})
+	}
+	endSpan(rt.span, resp, err)
+}
+
+func endSpan(span Span, resp *http.Response, err error) {
+	if resp != nil {
+		span.SetAttributes(Attribute{Key: AttrHTTPResponseStatus, Value: resp.StatusCode})
+	}
+	switch {
+	case err != nil:
+		span.SetAttributes(Attribute{Key: AttrErrorType, Value: errorClass(err)})
+		span.RecordError(err)
+	case resp != nil && resp.StatusCode >= 400:
+		span.SetAttributes(Attribute{Key: AttrErrorType, Value: strconv.Itoa(resp.StatusCode)})
+	}
+	span.End()
+}
@@ -0,0 +1,137 @@
+package httpc_test
+
+import (
+	"context"
+	"errors"
+	"github.com/nbs-go/httpc"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+)
+
+// recordTracer is a httpc.Tracer that records finished spans
+type recordTracer struct {
+	mu    sync.Mutex
+	seq   byte
+	spans []*recordSpan
+}
+
+type recordSpan struct {
+	name   string
+	kind   httpc.SpanKind
+	parent *recordSpan
+	sc     httpc.SpanContext
+	attrs  map[string]interface{}
+	err    error
+	ended  bool
+}
+
+type spanKey struct{}
+
+func (t *recordTracer) Start(ctx context.Context, name string, kind httpc.SpanKind, attrs ...httpc.Attribute) (context.Context, httpc.Span) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.seq++
+	s := &recordSpan{name: name, kind: kind, attrs: make(map[string]interface{})}
+	s.parent, _ = ctx.Value(spanKey{}).(*recordSpan)
+	s.sc.TraceId[0] = 1
+	s.sc.SpanId[7] = t.seq
+	s.sc.Sampled = true
+	s.SetAttributes(attrs...)
+	t.spans = append(t.spans, s)
+	return context.WithValue(ctx, spanKey{}, s), s
+}
+
+func (s *recordSpan) SetAttributes(attrs ...httpc.Attribute) {
+	for _, a := range attrs {
+		s.attrs[a.Key] = a.Value
+	}
+}
+
+func (s *recordSpan) RecordError(err error) {
+	s.err = err
+}
+
+func (s *recordSpan) SpanContext() httpc.SpanContext {
+	return s.sc
+}
+
+func (s *recordSpan) End() {
+	s.ended = true
+}
+
+func TestTracing(t *testing.T) {
+	var traceParents []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		traceParents = append(traceParents, r.Header.Get(httpc.HeaderTraceParent))
+		if r.Header.Get(httpc.HeaderAuthorization) != "Bearer t2" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		_, _ = w.Write([]byte("hello"))
+	}))
+	defer srv.Close()
+	tokens := []string{"t1", "t2"}
+	src := httpc.TokenSourceFn(func(ctx context.Context) (*httpc.Token, error) {
+		tk := tokens[0]
+		tokens = tokens[1:]
+		return &httpc.Token{AccessToken: tk}, nil
+	})
+	tr := new(recordTracer)
+	rc := httpc.MustNewClient(srv.URL, httpc.Tracing(tr), httpc.ClientTokenAuth(src))
+
+	_, _, err := rc.DoRequest(context.Background(), "GET", "/users/{id}", httpc.PathParam("id", "1"))
+	if err != nil {
+		t.Errorf("unexpected error: %s", err)
+		return
+	}
+	if len(tr.spans) != 3 {
+		t.Errorf("unexpected span count: %d", len(tr.spans))
+		return
+	}
+	root, first, second := tr.spans[0], tr.spans[1], tr.spans[2]
+	if root.kind != httpc.SpanKindInternal || root.name != "GET /users/{id}" || first.parent != root ||
+		second.parent != root || second.kind != httpc.SpanKindClient {
+		t.Errorf("unexpected spans: %+v, %+v, %+v", root, first, second)
+	}
+	for _, s := range tr.spans {
+		if !s.ended {
+			t.Errorf("unexpected condition: span is not ended. %+v", s)
+		}
+	}
+	expected := []string{
+		"00-01000000000000000000000000000000-0000000000000002-01",
+		"00-01000000000000000000000000000000-0000000000000003-01",
+	}
+	if len(traceParents) != 2 || traceParents[0] != expected[0] || traceParents[1] != expected[1] {
+		t.Errorf("unexpected traceparent: %v", traceParents)
+	}
+	if first.attrs[httpc.AttrHTTPResponseStatus] != 401 || second.attrs[httpc.AttrHTTPResendCount] != 1 {
+		t.Errorf("unexpected attempt attributes: %v, %v", first.attrs, second.attrs)
+	}
+	if root.attrs[httpc.AttrURLTemplate] != "/users/{id}" || root.attrs[httpc.AttrHTTPResponseStatus] != 200 ||
+		root.attrs[httpc.AttrHTTPResponseBodySize] != 5 || root.attrs[httpc.AttrHTTPRequestMethod] != "GET" {
+		t.Errorf("unexpected request attributes: %v", root.attrs)
+	}
+}
+
+func TestTracingError(t *testing.T) {
+	tr := new(recordTracer)
+	rc := httpc.MustNewClient("http://127.0.0.1:1", httpc.Tracing(tr))
+
+	_, _, err := rc.DoRequest(context.Background(), "GET", "/")
+	if err == nil {
+		t.Errorf("expected error is not returned")
+		return
+	}
+	if len(tr.spans) != 2 {
+		t.Errorf("unexpected span count: %d", len(tr.spans))
+		return
+	}
+	for _, s := range tr.spans {
+		if !s.ended || !errors.Is(s.err, err) || s.attrs[httpc.AttrErrorType] != httpc.ErrorClassConnection {
+			t.Errorf("unexpected span: %+v", s)
+		}
+	}
+}